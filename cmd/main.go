@@ -2,15 +2,29 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"flag"
 	"github.com/hasElvin/messenger-svc/config"
 	"github.com/hasElvin/messenger-svc/internal/adapters/cache"
 	"github.com/hasElvin/messenger-svc/internal/adapters/db"
 	"github.com/hasElvin/messenger-svc/internal/adapters/http"
+	"github.com/hasElvin/messenger-svc/internal/adapters/metrics"
 	"github.com/hasElvin/messenger-svc/internal/adapters/rest"
+	"github.com/hasElvin/messenger-svc/internal/adapters/telemetry"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+	"github.com/hasElvin/messenger-svc/internal/core/phone"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
 	"github.com/hasElvin/messenger-svc/internal/core/services"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	// Embedded zone database so quiet hours work on images without tzdata
+	_ "time/tzdata"
 )
 
 // @title Messenger API
@@ -19,8 +33,25 @@ import (
 // @host messenger-svc-gfsy.onrender.com
 // @schemes https
 // @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
-	cfg := config.LoadConfig()
+	configPath := flag.String("config", config.DefaultPath, "path to the YAML config file")
+	flag.Parse()
+
+	cfg := config.LoadConfig(*configPath)
+
+	if err := logging.Setup(os.Stderr, cfg.Logging.Level, cfg.Logging.Format); err != nil {
+		log.Fatalf("Invalid logging config: %v", err)
+	}
+
+	// Canceled on SIGINT or SIGTERM, which stops the background workers
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Tracing first, so the database and Redis clients pick up the tracer provider
+	shutdownTracing := telemetry.InitTracing(cfg)
 
 	// Initialize database
 	database := db.InitPostgres(cfg)
@@ -31,10 +62,81 @@ func main() {
 	// Initialize adapters
 	messageRepo := db.NewPostgresRepository(database)
 	cacheService := cache.NewRedisCache(redisClient)
-	messageSender := http.NewWebhookSender(cfg.App.WebhookURL)
+	dispatchMetrics := metrics.NewPrometheus("webhook")
+
+	// Reloadable settings and tenants are read through configService; the rest of cfg is fixed at startup
+	configService := services.NewConfigService(cfg, func() (config.Config, error) {
+		return config.Load(*configPath)
+	})
+	webhookSender := http.NewWebhookSender(configService)
+
+	rateLimiter := cache.NewRedisRateLimiter(redisClient,
+		cfg.RateLimit.GlobalPerSecond, cfg.RateLimit.GlobalBurst,
+		cfg.RateLimit.PerRecipientLimit,
+		time.Duration(cfg.RateLimit.PerRecipientWindowSeconds)*time.Second)
+
+	// Over-limit messages are deferred to a later tick rather than failed
+	rateLimitedSender := services.NewRateLimitedSender(dispatchMetrics.InstrumentSender(webhookSender), rateLimiter)
+
+	// Guard the webhook so a provider outage doesn't stall every tick on timeouts. The breaker
+	// goes outside the limiter so rejected sends don't use up rate limit capacity.
+	breaker := services.NewTenantCircuitBreaker(rateLimitedSender,
+		cfg.CircuitBreaker.FailureThreshold,
+		time.Duration(cfg.CircuitBreaker.OpenTimeoutSecs)*time.Second,
+		cfg.CircuitBreaker.HalfOpenMaxRequests)
+	dispatchMetrics.WatchBreaker(breaker)
+	dispatchMetrics.WatchQueue(messageRepo, configService)
+
+	if cfg.App.DefaultRegion != "" && !phone.Supported(cfg.App.DefaultRegion) {
+		log.Fatalf("Unsupported default region %q", cfg.App.DefaultRegion)
+	}
 
 	// Initialize services
-	messageService := services.NewMessageService(messageRepo, cacheService, messageSender)
+	callbackRepo := db.NewCallbackRepository(database)
+	callbackService := services.NewCallbackService(callbackRepo,
+		http.NewCallbackPoster(cfg.StatusCallbacks.SigningSecret), cfg.StatusCallbacks.MaxAttempts)
+	templateService := services.NewTemplateService(db.NewTemplateRepository(database),
+		cfg.Locales.Default, cfg.Locales.Fallbacks)
+	recipientRepo := db.NewRecipientRepository(database)
+	recipientService := services.NewRecipientService(recipientRepo, cfg.App.DefaultRegion)
+	suppressionService := services.NewSuppressionService(db.NewSuppressionRepository(database), cacheService,
+		cfg.App.DefaultRegion, cfg.Suppression.OptOutKeywords, cfg.Suppression.OptInKeywords)
+	quietHours := make(map[string]domain.QuietWindow, len(cfg.QuietHours))
+	for category, hours := range cfg.QuietHours {
+		window, err := domain.ParseQuietWindow(hours.Start, hours.End)
+		if err != nil {
+			log.Fatalf("Invalid quiet hours for %s: %v", category, err)
+		}
+		quietHours[strings.ToLower(category)] = window
+	}
+	messageOptions := []services.MessageServiceOption{
+		services.WithQuietHours(quietHours),
+		services.WithSuppressions(suppressionService),
+		services.WithTemplates(templateService),
+		services.WithRecipients(recipientRepo),
+		services.WithMetrics(dispatchMetrics),
+		services.WithConfig(configService),
+		services.WithContentLimits(cfg.App.MessageCharLimit, cfg.App.MaxSegments),
+		services.WithDefaultRegion(cfg.App.DefaultRegion),
+	}
+	// Receivers can only trust callbacks they can verify, so none are sent unsigned
+	if cfg.StatusCallbacks.SigningSecret != "" {
+		messageOptions = append(messageOptions, services.WithStatusNotifier(callbackService))
+	} else {
+		slog.Warn("status_callbacks.signing_secret is not set, messages with a callback_url will be rejected")
+	}
+	if cfg.Dedupe.Mode != "" {
+		mode := services.DedupeMode(cfg.Dedupe.Mode)
+		if mode != services.DedupeReject && mode != services.DedupeCollapse {
+			log.Fatalf("Invalid dedupe mode %q, expected reject or collapse", cfg.Dedupe.Mode)
+		}
+		messageOptions = append(messageOptions, services.WithDedupe(mode,
+			time.Duration(cfg.Dedupe.WindowSeconds)*time.Second))
+	}
+	if cfg.Multipart.Enabled {
+		messageOptions = append(messageOptions, services.WithMultipart(services.MultipartMode(cfg.Multipart.Mode)))
+	}
+	messageService := services.NewMessageService(messageRepo, cacheService, breaker, messageOptions...)
 	utilityService := services.NewUtilityService(messageRepo)
 
 	// Seed test data for easy testing purposes
@@ -42,21 +144,129 @@ func main() {
 		log.Fatalf("Failed to seed sample messages: %v", err)
 	}
 
-	// Start the auto sender immediately with context
-	if err := messageService.StartAutoSender(context.Background(), cfg.App.SendIntervalSecs); err != nil {
+	// Start the auto sender immediately with context, following the configured interval and batch size
+	if err := messageService.StartAutoSender(ctx, 0, 0); err != nil {
 		log.Fatalf("Auto sender failed to automatically start: %v", err)
 	}
 
+	var workers sync.WaitGroup
+
+	// Status callbacks have their own queue so slow client endpoints never hold up SMS dispatch
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		callbackService.Run(ctx, time.Duration(cfg.StatusCallbacks.IntervalSeconds)*time.Second)
+	}()
+
+	// Recurring schedules enqueue through the message service like API calls do
+	scheduleService := services.NewScheduleService(db.NewScheduleRepository(database), messageService,
+		cfg.App.DefaultRegion)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		scheduleService.Run(ctx, time.Duration(cfg.Schedules.IntervalSeconds)*time.Second)
+	}()
+
+	// SIGHUP reloads the configuration like POST /admin/config/reload
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case <-reload:
+				if _, err := configService.Reload(ctx); err != nil {
+					slog.Error("Config reload rejected, keeping the running config", "error", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	healthService := services.NewHealthService(
+		[]ports.HealthCheck{services.NewSenderHealthCheck(messageService)},
+		[]ports.HealthCheck{db.NewHealthCheck(database), cache.NewHealthCheck(redisClient)})
+
+	authService := services.NewAuthService(db.NewAPIKeyRepository(database), db.NewAuditRepository(database),
+		configService)
+	if cfg.Auth.BootstrapAdminKey != "" {
+		if err := authService.EnsureKey(ctx, "bootstrap-admin", cfg.Auth.BootstrapAdminKey, domain.RoleAdmin); err != nil {
+			log.Fatalf("Failed to store bootstrap admin key: %v", err)
+		}
+	}
+	if !cfg.Auth.Enabled {
+		slog.Warn("API authentication is disabled, every endpoint is open to anyone who can reach the service")
+	} else {
+		// Keys are created through the API by an admin, so without one every route stays locked
+		hasAdmin, err := authService.HasAdminKey(ctx)
+		if err != nil {
+			log.Fatalf("Failed to look up API keys: %v", err)
+		}
+		if !hasAdmin {
+			log.Fatalf("auth.enabled is set but no admin API key exists; set auth.bootstrap_admin_key " +
+				"(MESSENGER_AUTH_BOOTSTRAP_ADMIN_KEY) to create one, or disable auth")
+		}
+	}
+
 	// Initialize and start HTTP server
-	server := rest.NewServer(messageService, utilityService)
+	server := rest.NewServer(messageService, utilityService, templateService, recipientService, suppressionService,
+		scheduleService, healthService, configService, authService, breaker, dispatchMetrics.Handler(),
+		cfg.App.CallbackSecret, cfg.Auth.Enabled)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	fmt.Printf("Server starting on :%s\n", port)
-	if err := server.Run(":" + port); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	slog.Info("Server starting", "port", port)
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Run(":" + port)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatalf("Server failed to start: %v", err)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+
+	timeout := time.Duration(cfg.App.ShutdownTimeoutSecs) * time.Second
+	if timeout <= 0 {
+		timeout = 25 * time.Second
+	}
+	slog.Info("Shutting down", "timeout", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Stop taking requests, then let the sends and worker passes already under way finish
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not shut down cleanly", "error", err)
+	}
+	if err := messageService.Drain(shutdownCtx); err != nil {
+		slog.Error("Auto sender did not finish in time, messages being sent may need checking", "error", err)
+	}
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		slog.Error("Background workers did not finish in time")
+	}
+
+	if err := db.ClosePostgres(database); err != nil {
+		slog.Error("Failed to close database", "error", err)
+	}
+	if err := redisClient.Close(); err != nil {
+		slog.Error("Failed to close Redis", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
 	}
+	slog.Info("Shutdown complete")
 }
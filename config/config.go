@@ -1,21 +1,96 @@
 package config
 
 import (
+	"fmt"
 	"github.com/spf13/viper"
 	"log"
-	"os"
-	"strconv"
 )
 
 type Config struct {
 	App struct {
-		WebhookURL       string `yaml:"webhook_url" mapstructure:"webhook_url"`
-		WebhookKey       string `yaml:"webhook_key" mapstructure:"webhook_key"` //optional
-		SendIntervalSecs int    `yaml:"send_interval_seconds" mapstructure:"send_interval_seconds"`
-		MessageCharLimit int    `yaml:"message_char_limit" mapstructure:"message_char_limit"`
-		MaxRetries       int    `yaml:"max_retries" mapstructure:"max_retries"`
+		WebhookURL          string `yaml:"webhook_url" mapstructure:"webhook_url"`
+		WebhookKey          string `yaml:"webhook_key" mapstructure:"webhook_key"` //optional
+		SendIntervalSecs    int    `yaml:"send_interval_seconds" mapstructure:"send_interval_seconds"`
+		MessageCharLimit    int    `yaml:"message_char_limit" mapstructure:"message_char_limit"`
+		MaxSegments         int    `yaml:"max_segments" mapstructure:"max_segments"`
+		MaxRetries          int    `yaml:"max_retries" mapstructure:"max_retries"`
+		BatchSize           int    `yaml:"batch_size" mapstructure:"batch_size"`                             // messages per tick, default 2
+		CallbackSecret      string `yaml:"callback_secret" mapstructure:"callback_secret"`                   //optional
+		DefaultRegion       string `yaml:"default_region" mapstructure:"default_region"`                     // ISO country for national numbers
+		ShutdownTimeoutSecs int    `yaml:"shutdown_timeout_seconds" mapstructure:"shutdown_timeout_seconds"` // drain deadline on SIGTERM
 	} `yaml:"app" mapstructure:"app"`
 
+	Locales struct {
+		Default   string              `yaml:"default" mapstructure:"default"`
+		Fallbacks map[string][]string `yaml:"fallbacks" mapstructure:"fallbacks"` // e.g. az: [tr, en]
+	} `yaml:"locales" mapstructure:"locales"`
+
+	Suppression struct {
+		OptOutKeywords []string `yaml:"opt_out_keywords" mapstructure:"opt_out_keywords"`
+		OptInKeywords  []string `yaml:"opt_in_keywords" mapstructure:"opt_in_keywords"`
+	} `yaml:"suppression" mapstructure:"suppression"`
+
+	// QuietHours maps a message category to the local times it must not be sent
+	QuietHours map[string]struct {
+		Start string `yaml:"start" mapstructure:"start"` // HH:MM
+		End   string `yaml:"end" mapstructure:"end"`     // HH:MM
+	} `yaml:"quiet_hours" mapstructure:"quiet_hours"`
+
+	Dedupe struct {
+		Mode          string `yaml:"mode" mapstructure:"mode"` // "reject", "collapse" or empty to disable
+		WindowSeconds int    `yaml:"window_seconds" mapstructure:"window_seconds"`
+	} `yaml:"dedupe" mapstructure:"dedupe"`
+
+	Multipart struct {
+		Enabled bool   `yaml:"enabled" mapstructure:"enabled"`
+		Mode    string `yaml:"mode" mapstructure:"mode"` // "udh" or "suffix"
+	} `yaml:"multipart" mapstructure:"multipart"`
+
+	CircuitBreaker struct {
+		FailureThreshold    int `yaml:"failure_threshold" mapstructure:"failure_threshold"`
+		OpenTimeoutSecs     int `yaml:"open_timeout_seconds" mapstructure:"open_timeout_seconds"`
+		HalfOpenMaxRequests int `yaml:"half_open_max_requests" mapstructure:"half_open_max_requests"`
+	} `yaml:"circuit_breaker" mapstructure:"circuit_breaker"`
+
+	RateLimit struct {
+		GlobalPerSecond           float64 `yaml:"global_per_second" mapstructure:"global_per_second"`
+		GlobalBurst               int     `yaml:"global_burst" mapstructure:"global_burst"`
+		PerRecipientLimit         int     `yaml:"per_recipient_limit" mapstructure:"per_recipient_limit"`
+		PerRecipientWindowSeconds int     `yaml:"per_recipient_window_seconds" mapstructure:"per_recipient_window_seconds"`
+	} `yaml:"rate_limit" mapstructure:"rate_limit"`
+
+	StatusCallbacks struct {
+		SigningSecret   string `yaml:"signing_secret" mapstructure:"signing_secret"` //required for callback_url
+		IntervalSeconds int    `yaml:"interval_seconds" mapstructure:"interval_seconds"`
+		MaxAttempts     int    `yaml:"max_attempts" mapstructure:"max_attempts"`
+	} `yaml:"status_callbacks" mapstructure:"status_callbacks"`
+
+	Logging struct {
+		Level  string `yaml:"level" mapstructure:"level"`   // debug, info, warn or error
+		Format string `yaml:"format" mapstructure:"format"` // json or text
+	} `yaml:"logging" mapstructure:"logging"`
+
+	Tracing struct {
+		Exporter    string  `yaml:"exporter" mapstructure:"exporter"`         // "otlp", "stdout" or empty to disable
+		Endpoint    string  `yaml:"endpoint" mapstructure:"endpoint"`         // OTLP/HTTP host:port, e.g. localhost:4318
+		Insecure    bool    `yaml:"insecure" mapstructure:"insecure"`         // plain HTTP to the collector
+		ServiceName string  `yaml:"service_name" mapstructure:"service_name"` //optional
+		SampleRatio float64 `yaml:"sample_ratio" mapstructure:"sample_ratio"` // 0 < ratio <= 1, default 1
+	} `yaml:"tracing" mapstructure:"tracing"`
+
+	Schedules struct {
+		IntervalSeconds int `yaml:"interval_seconds" mapstructure:"interval_seconds"`
+	} `yaml:"schedules" mapstructure:"schedules"`
+
+	// Tenants maps a tenant ID to the app settings it overrides; see TenantSettings
+	Tenants map[string]Tenant `yaml:"tenants" mapstructure:"tenants"`
+
+	Auth struct {
+		Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+		// BootstrapAdminKey is stored as an admin key at startup so the first keys can be created
+		BootstrapAdminKey string `yaml:"bootstrap_admin_key" mapstructure:"bootstrap_admin_key"` //optional
+	} `yaml:"auth" mapstructure:"auth"`
+
 	Database struct {
 		Host     string `yaml:"host" mapstructure:"host"`
 		Port     int    `yaml:"port" mapstructure:"port"`
@@ -30,47 +105,45 @@ type Config struct {
 	} `yaml:"redis" mapstructure:"redis"`
 }
 
-func LoadConfig() Config {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./config")
-
-	var config Config
+// DefaultPath is the config file read when no --config flag is given
+const DefaultPath = "./config/config.yaml"
 
-	// Load from YAML
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalf("Config read error: %v", err)
+// LoadConfig reads and validates the configuration at startup, exiting if it can't
+func LoadConfig(path string) Config {
+	config, err := Load(path)
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
-	if err := viper.Unmarshal(&config); err != nil {
-		log.Fatalf("Config unmarshal error: %v", err)
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
 	}
-
-	// Overwrite from ENV if available
-	overrideString(&config.App.WebhookURL, "WEBHOOK_URL")
-	overrideString(&config.App.WebhookKey, "WEBHOOK_KEY")
-
-	overrideString(&config.Database.Host, "PGHOST")
-	overrideInt(&config.Database.Port, "PGPORT")
-	overrideString(&config.Database.User, "PGUSER")
-	overrideString(&config.Database.Password, "PGPASSWORD")
-	overrideString(&config.Database.Name, "PGDATABASE")
-	overrideString(&config.Database.SSLMode, "PGSSLMODE")
-
-	overrideString(&config.Redis.URL, "REDIS_URL")
-
 	return config
 }
 
-func overrideString(field *string, envKey string) {
-	if val := os.Getenv(envKey); val != "" {
-		*field = val
+// Load reads the YAML file at path, DefaultPath if empty, and applies the environment
+// overrides on top of it; see applyEnv
+func Load(path string) (Config, error) {
+	if path == "" {
+		path = DefaultPath
 	}
-}
 
-func overrideInt(field *int, envKey string) {
-	if val := os.Getenv(envKey); val != "" {
-		if intVal, err := strconv.Atoi(val); err == nil {
-			*field = intVal
-		}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+
+	var config Config
+
+	// Load from YAML
+	if err := v.ReadInConfig(); err != nil {
+		return Config{}, fmt.Errorf("config read error: %w", err)
 	}
+	// Overwrite from ENV if available
+	if err := applyEnv(v); err != nil {
+		return Config{}, fmt.Errorf("config env error: %w", err)
+	}
+	if err := v.Unmarshal(&config); err != nil {
+		return Config{}, fmt.Errorf("config unmarshal error: %w", err)
+	}
+
+	return config, nil
 }
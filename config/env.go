@@ -0,0 +1,99 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix starts the environment variable of every setting: app.max_retries is read
+// from MESSENGER_APP_MAX_RETRIES
+const EnvPrefix = "MESSENGER"
+
+// legacyEnv are the older variable names still honoured when the prefixed one isn't set
+var legacyEnv = map[string][]string{
+	"app.webhook_url":                 {"WEBHOOK_URL"},
+	"app.webhook_key":                 {"WEBHOOK_KEY"},
+	"app.callback_secret":             {"CALLBACK_SECRET"},
+	"status_callbacks.signing_secret": {"STATUS_CALLBACK_SECRET"},
+	"database.host":                   {"PGHOST"},
+	"database.port":                   {"PGPORT"},
+	"database.user":                   {"PGUSER"},
+	"database.password":               {"PGPASSWORD"},
+	"database.name":                   {"PGDATABASE"},
+	"database.sslmode":                {"PGSSLMODE"},
+	"redis.url":                       {"REDIS_URL"},
+}
+
+// applyEnv overrides every setting whose environment variable is set and not empty.
+// NAME_FILE may name a file holding the value instead, as mounted secrets do. Lists are
+// comma separated. Maps such as quiet_hours have no fixed keys and can only be set in the
+// file, except that the settings of a tenant listed there can be overridden too, e.g.
+// MESSENGER_TENANTS_MARKETING_WEBHOOK_KEY.
+func applyEnv(v *viper.Viper) error {
+	keys := settingKeys(reflect.TypeOf(Config{}), "")
+	for id := range v.GetStringMap("tenants") {
+		keys = append(keys, settingKeys(reflect.TypeOf(Tenant{}), "tenants."+id+".")...)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		names := append([]string{EnvName(key)}, legacyEnv[key]...)
+		value, found, err := lookupEnv(names)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if found {
+			v.Set(key, value)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EnvName returns the prefixed environment variable for a dotted setting key
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(envSeparators.Replace(key))
+}
+
+// envSeparators turns a setting key into characters allowed in variable names
+var envSeparators = strings.NewReplacer(".", "_", "-", "_")
+
+// lookupEnv returns the value of the first of names that is set, directly or through NAME_FILE
+func lookupEnv(names []string) (string, bool, error) {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value, true, nil
+		}
+		if path := os.Getenv(name + "_FILE"); path != "" {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", false, fmt.Errorf("%s_FILE: %w", name, err)
+			}
+			// Secret files usually end in a newline that isn't part of the value
+			return strings.TrimRight(string(content), "\r\n"), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// settingKeys lists the dotted key of every setting that isn't a map
+func settingKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := prefix + field.Tag.Get("mapstructure")
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			keys = append(keys, settingKeys(field.Type, key+".")...)
+		case reflect.Map:
+		default:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,55 @@
+package config
+
+// Tenant holds a team's overrides of the app-wide settings; zero values inherit them
+type Tenant struct {
+	WebhookURL       string `yaml:"webhook_url" mapstructure:"webhook_url"`               //optional
+	WebhookKey       string `yaml:"webhook_key" mapstructure:"webhook_key"`               //optional
+	MessageCharLimit int    `yaml:"message_char_limit" mapstructure:"message_char_limit"` //optional
+	MaxRetries       int    `yaml:"max_retries" mapstructure:"max_retries"`               //optional
+}
+
+// HasTenant reports whether id is a configured tenant
+func (c Config) HasTenant(id string) bool {
+	_, ok := c.Tenants[id]
+	return ok
+}
+
+// TenantSettings returns the settings in effect for tenant id: its own overrides on top of
+// the app-wide values. An empty or unknown id gets the app-wide values.
+func (c Config) TenantSettings(id string) Tenant {
+	settings := Tenant{
+		WebhookURL:       c.App.WebhookURL,
+		WebhookKey:       c.App.WebhookKey,
+		MessageCharLimit: c.App.MessageCharLimit,
+		MaxRetries:       c.App.MaxRetries,
+	}
+
+	tenant, ok := c.Tenants[id]
+	if !ok {
+		return settings
+	}
+	if tenant.WebhookURL != "" {
+		settings.WebhookURL = tenant.WebhookURL
+		// A key for the app-wide webhook means nothing to another one
+		settings.WebhookKey = tenant.WebhookKey
+	} else if tenant.WebhookKey != "" {
+		settings.WebhookKey = tenant.WebhookKey
+	}
+	if tenant.MessageCharLimit > 0 {
+		settings.MessageCharLimit = tenant.MessageCharLimit
+	}
+	if tenant.MaxRetries > 0 {
+		settings.MaxRetries = tenant.MaxRetries
+	}
+	return settings
+}
+
+// MaxRetriesCeiling is the highest retry limit of the app or any tenant, so a query for
+// messages still worth sending doesn't drop those of a tenant allowed more attempts
+func (c Config) MaxRetriesCeiling() int {
+	ceiling := c.App.MaxRetries
+	for _, tenant := range c.Tenants {
+		ceiling = max(ceiling, tenant.MaxRetries)
+	}
+	return ceiling
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// tenantID keeps tenant IDs usable in environment variable names and URLs
+var tenantID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidationError lists every problem found in a configuration, so they can all be fixed at once
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// Validate checks every setting and reports all the problems found. Zero is accepted
+// wherever the consumer falls back to a default.
+func (c Config) Validate() error {
+	v := &validator{}
+
+	v.url("app.webhook_url", c.App.WebhookURL, "http", "https")
+	v.min("app.send_interval_seconds", c.App.SendIntervalSecs, 1)
+	v.min("app.message_char_limit", c.App.MessageCharLimit, 0)
+	v.min("app.max_segments", c.App.MaxSegments, 0)
+	v.min("app.max_retries", c.App.MaxRetries, 1)
+	v.between("app.batch_size", c.App.BatchSize, 0, 1000)
+	v.min("app.shutdown_timeout_seconds", c.App.ShutdownTimeoutSecs, 0)
+	if c.App.DefaultRegion != "" && len(c.App.DefaultRegion) != 2 {
+		v.addf("app.default_region must be a two-letter country code, got %q", c.App.DefaultRegion)
+	}
+
+	for category, hours := range c.QuietHours {
+		v.clock(fmt.Sprintf("quiet_hours.%s.start", category), hours.Start)
+		v.clock(fmt.Sprintf("quiet_hours.%s.end", category), hours.End)
+	}
+
+	v.oneOf("dedupe.mode", c.Dedupe.Mode, "", "reject", "collapse")
+	if c.Dedupe.Mode != "" {
+		v.min("dedupe.window_seconds", c.Dedupe.WindowSeconds, 1)
+	}
+	if c.Multipart.Enabled {
+		v.oneOf("multipart.mode", c.Multipart.Mode, "udh", "suffix")
+	}
+
+	v.min("circuit_breaker.failure_threshold", c.CircuitBreaker.FailureThreshold, 0)
+	v.min("circuit_breaker.open_timeout_seconds", c.CircuitBreaker.OpenTimeoutSecs, 0)
+	v.min("circuit_breaker.half_open_max_requests", c.CircuitBreaker.HalfOpenMaxRequests, 0)
+
+	if c.RateLimit.GlobalPerSecond < 0 {
+		v.addf("rate_limit.global_per_second can't be negative, got %g", c.RateLimit.GlobalPerSecond)
+	}
+	v.min("rate_limit.global_burst", c.RateLimit.GlobalBurst, 0)
+	v.min("rate_limit.per_recipient_limit", c.RateLimit.PerRecipientLimit, 0)
+	v.min("rate_limit.per_recipient_window_seconds", c.RateLimit.PerRecipientWindowSeconds, 0)
+
+	v.min("status_callbacks.interval_seconds", c.StatusCallbacks.IntervalSeconds, 0)
+	v.min("status_callbacks.max_attempts", c.StatusCallbacks.MaxAttempts, 0)
+	v.min("schedules.interval_seconds", c.Schedules.IntervalSeconds, 0)
+
+	v.oneOf("logging.level", strings.ToLower(c.Logging.Level), "", "debug", "info", "warn", "error")
+	v.oneOf("logging.format", strings.ToLower(c.Logging.Format), "", "json", "text")
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "", "otlp", "stdout")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.addf("tracing.sample_ratio must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+	}
+
+	for id, tenant := range c.Tenants {
+		if !tenantID.MatchString(id) {
+			v.addf("tenants.%s: tenant IDs are lowercase letters, digits, - and _, up to 64 characters", id)
+		}
+		if tenant.WebhookURL != "" {
+			v.url(fmt.Sprintf("tenants.%s.webhook_url", id), tenant.WebhookURL, "http", "https")
+		}
+		v.min(fmt.Sprintf("tenants.%s.message_char_limit", id), tenant.MessageCharLimit, 0)
+		v.min(fmt.Sprintf("tenants.%s.max_retries", id), tenant.MaxRetries, 0)
+	}
+
+	if key := c.Auth.BootstrapAdminKey; key != "" && len(key) < 32 {
+		v.addf("auth.bootstrap_admin_key must be at least 32 characters")
+	}
+
+	v.required("database.host", c.Database.Host)
+	v.between("database.port", c.Database.Port, 1, 65535)
+	v.required("database.user", c.Database.User)
+	v.required("database.name", c.Database.Name)
+	v.oneOf("database.sslmode", c.Database.SSLMode, "", "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+
+	v.url("redis.url", c.Redis.URL, "redis", "rediss")
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validator collects problems; values are only quoted for settings that can't be secrets
+type validator struct {
+	problems []string
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf("%s is required (env %s)", key, EnvName(key))
+	}
+}
+
+func (v *validator) min(key string, value, min int) {
+	if value < min {
+		v.addf("%s must be at least %d, got %d", key, min, value)
+	}
+}
+
+func (v *validator) between(key string, value, min, max int) {
+	if value < min || value > max {
+		v.addf("%s must be between %d and %d, got %d", key, min, max, value)
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	named := make([]string, 0, len(allowed))
+	for _, a := range allowed {
+		if a != "" {
+			named = append(named, a)
+		}
+	}
+	v.addf("%s must be one of %s, got %q", key, strings.Join(named, ", "), value)
+}
+
+func (v *validator) clock(key, value string) {
+	if _, err := time.Parse("15:04", value); err != nil {
+		v.addf("%s must be a HH:MM time, got %q", key, value)
+	}
+}
+
+// url requires an absolute URL with one of schemes; the URL itself may carry
+// credentials, so it is never echoed
+func (v *validator) url(key, value string, schemes ...string) {
+	if value == "" {
+		v.required(key, value)
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		v.addf("%s must be an absolute URL", key)
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	v.addf("%s must use %s", key, strings.Join(schemes, " or "))
+}
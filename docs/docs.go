@@ -15,18 +15,40 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/clear": {
-            "delete": {
-                "description": "Clears database for testing purposes",
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the newest state-changing API calls with the key that made them, including calls refused for lack of a role",
                 "tags": [
-                    "Utility"
+                    "Admin"
+                ],
+                "summary": "Audit log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only calls made with this API key",
+                        "name": "key_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries, default 100, at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Clear database",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.AuditEntry"
+                            }
                         }
                     },
                     "400": {
@@ -38,35 +60,94 @@ const docTemplate = `{
                 }
             }
         },
-        "/ping": {
-            "get": {
-                "description": "Returns a simple pong string",
+        "/admin/config/reload": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Re-reads config.yaml and the environment. If the result is valid, the send interval, batch size, retry limit and log level take effect without a restart; other changed sections are listed as needing one. An invalid configuration is rejected and the running one is kept. Sending SIGHUP to the process does the same.",
                 "tags": [
-                    "Utility"
+                    "Admin"
                 ],
-                "summary": "Health check",
+                "summary": "Reload configuration",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "$ref": "#/definitions/domain.ConfigReload"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
                         }
                     }
                 }
             }
         },
-        "/seed": {
-            "post": {
-                "description": "Seeds 10 sample messages into database for testing purposes",
+        "/admin/keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every key, including revoked and expired ones, without their secrets",
                 "tags": [
-                    "Utility"
+                    "Admin"
                 ],
-                "summary": "Seed sample messages",
+                "summary": "List API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.APIKey"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a key with the given role, optionally confined to one tenant. The response is the only time the secret is returned. Send it in the X-API-Key header or as an Authorization bearer token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Key name, role and optional tenant and expiry",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
@@ -78,33 +159,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/sent": {
-            "get": {
-                "description": "Returns a list of messages that were sent by the auto-sender",
+        "/admin/keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes a key immediately. The key stays listed so its audit entries can still be traced to it.",
                 "tags": [
-                    "Messages"
+                    "Admin"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List all sent messages",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/domain.Message"
-                            }
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
                         }
                     }
                 }
             }
         },
-        "/start": {
+        "/callbacks/delivery": {
             "post": {
-                "description": "Starts the automatic message sending process",
+                "description": "Accepts a provider delivery receipt keyed by provider messageId. The raw body must be signed with HMAC-SHA256 using the shared callback secret and the hex digest sent in the X-Signature header. Duplicate receipts are acknowledged without changes.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "AutoSender"
+                    "Callbacks"
+                ],
+                "summary": "Delivery receipt callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the request body",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Delivery receipt",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DeliveryReceiptRequest"
+                        }
+                    }
                 ],
-                "summary": "Start auto-sender",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -117,17 +242,102 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.FailResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
                     }
                 }
             }
         },
-        "/stop": {
+        "/callbacks/inbound": {
             "post": {
-                "description": "Stops the automatic message sending process",
+                "description": "Accepts a reply relayed by the provider, signed like delivery receipts. Replies containing an opt-out keyword such as STOP add the sender to the suppression list; START lifts an opt-out the sender made by keyword.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "AutoSender"
+                    "Callbacks"
                 ],
-                "summary": "Stop auto-sender",
+                "summary": "Inbound message callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Hex HMAC-SHA256 of the request body",
+                        "name": "X-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Inbound message",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InboundMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.InboundMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/clear": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Clears database for testing purposes",
+                "tags": [
+                    "Utility"
+                ],
+                "summary": "Clear database",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -143,50 +353,1944 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Message": {
-            "type": "object",
-            "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "sent_at": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "to": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+        },
+        "/healthz": {
+            "get": {
+                "description": "Reports whether the process is working. Fails when the auto-sender is running but hasn't completed a tick for three intervals; database and Redis outages don't fail it, so they don't get the process restarted.",
+                "tags": [
+                    "Utility"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HealthReport"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HealthReport"
+                        }
+                    }
                 }
             }
         },
-        "handlers.FailResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
+        "/messages": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Queues a message for the auto-sender. The recipient is normalized to E.164, reading numbers without a country code as numbers of the configured default region, and rejected if it isn't a valid mobile number. If callback_url is set, signed status events are POSTed to it when the message is sent, fails or gets a delivery receipt. Either content or template_id is required; templates are rendered with variables in the requested locale (or the recipient's preferred one), following the configured fallback chain, and the result is checked against the same length limits. Messages in a category with quiet hours are held until the window ends in the recipient's timezone (explicit, stored preference or derived from the country code). Depending on the dedupe mode, the same content to the same recipient within the dedupe window is rejected with 409 or stored and later marked duplicate instead of sent. With transliterate, accented characters are replaced by GSM-7 equivalents before the message is stored. Messages of a tenant are sent to its webhook and checked against its character and retry limits where it overrides them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Enqueue a message",
+                "parameters": [
+                    {
+                        "description": "Message to send",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EnqueueMessageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
                 }
             }
         },
-        "handlers.SuccessResponse": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string"
+        "/messages/preview": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Shows content as written and transliterated to GSM-7, with the encoding and segment count of each",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Preview transliteration",
+                "parameters": [
+                    {
+                        "description": "Content to preview",
+                        "name": "preview",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PreviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.TransliterationPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
                 }
             }
+        },
+        "/messages/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Withdraws a message that hasn't been sent yet, along with its parts still pending. Tenant keys only find their own tenant's messages. A message that is no longer pending gets 409. The sender may be handing the message to the provider at that very moment, in which case it can still arrive.",
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "Cancel a pending message",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Message"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Returns a simple pong string",
+                "tags": [
+                    "Utility"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports whether the instance can serve traffic: Postgres and Redis respond to a ping and the auto-sender isn't stalled. Returns 503 with per-dependency status when any check fails.",
+                "tags": [
+                    "Utility"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HealthReport"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/domain.HealthReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/recipients/{phone}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the stored preferences for a phone number in any accepted format",
+                "tags": [
+                    "Recipients"
+                ],
+                "summary": "Get recipient preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Recipient"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stores preferences for a phone number. The locale is used to pick a template variant and the IANA timezone to apply quiet hours when a message doesn't specify them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Recipients"
+                ],
+                "summary": "Save recipient preferences",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RecipientPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Recipient"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "List recurring schedules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Schedule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a schedule that enqueues the template for every recipient at each occurrence of the cron expression in its timezone, between the optional start and end dates. Recipients are normalized like message recipients and scheduled messages go through the same checks.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Create a recurring schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Get a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a schedule; its next run is recomputed from now",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Update a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Schedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a schedule; messages it already enqueued are still sent",
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Delete a recurring schedule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedules/{id}/preview": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the next 10 occurrences of a schedule in its timezone, fewer if it ends sooner",
+                "tags": [
+                    "Schedules"
+                ],
+                "summary": "Preview upcoming runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SchedulePreviewResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seed": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Seeds 10 sample messages into database for testing purposes",
+                "tags": [
+                    "Utility"
+                ],
+                "summary": "Seed sample messages",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sender/breaker": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the state and counters of the circuit breaker guarding the webhook sender, with each tenant's own breaker under tenants. Not available to tenant keys.",
+                "tags": [
+                    "AutoSender"
+                ],
+                "summary": "Circuit breaker status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BreakerStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sender/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns whether the auto-sender is running, its interval and batch size, when the last tick finished and how many messages it picked up, when the next tick is due, and outcome counters since it was started. Not available to tenant keys.",
+                "tags": [
+                    "AutoSender"
+                ],
+                "summary": "Auto-sender status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SenderStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/sent": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of messages that were sent by the auto-sender, including those a delivery receipt has since marked delivered or undelivered. Tenant keys only ever see their own tenant's messages; other keys see every tenant's unless tenant_id is given.",
+                "tags": [
+                    "Messages"
+                ],
+                "summary": "List all sent messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only this tenant's messages",
+                        "name": "tenant_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Message"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/start": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Starts the automatic message sending process. The body is optional; fields left out follow the configured interval and batch size, including later reloads.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "AutoSender"
+                ],
+                "summary": "Start auto-sender",
+                "parameters": [
+                    {
+                        "description": "Interval and batch size overrides",
+                        "name": "options",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.StartSenderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stop": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stops the automatic message sending process",
+                "tags": [
+                    "AutoSender"
+                ],
+                "summary": "Stop auto-sender",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppressions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every phone number on the opt-out list, newest first",
+                "tags": [
+                    "Suppressions"
+                ],
+                "summary": "List suppressed numbers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Suppression"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a phone number to the opt-out list. Pending messages to it are marked suppressed instead of being sent. Adding a number twice keeps the original entry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppressions"
+                ],
+                "summary": "Suppress a number",
+                "parameters": [
+                    {
+                        "description": "Number to suppress",
+                        "name": "suppression",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuppressionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Suppression"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppressions/{phone}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes a phone number from the opt-out list",
+                "tags": [
+                    "Suppressions"
+                ],
+                "summary": "Remove a suppressed number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone number",
+                        "name": "phone",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every template at its latest version",
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "List templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.Template"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a message template. Placeholders are written as {{name}} and become the template's required variables. Variants hold translations keyed by locale and may only use the body's variables.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Create a template",
+                "parameters": [
+                    {
+                        "description": "Template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the latest version of a template, or the given version",
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Get a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Template version",
+                        "name": "version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates a template. Changed bodies or variants are stored as a new version; messages already queued keep the version they were rendered from.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Update a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Template",
+                        "name": "template",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TemplateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a template. Its versions are kept so sent messages remain traceable.",
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "Delete a template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every version of a template in every locale, newest first",
+                "tags": [
+                    "Templates"
+                ],
+                "summary": "List template versions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.TemplateVersion"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.FailResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.APIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/domain.Role"
+                },
+                "tenant_id": {
+                    "description": "TenantID confines the key to one tenant's messages; empty keys act for the whole deployment",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "description": "APIKeyID and KeyName are empty when authentication is disabled",
+                    "type": "integer"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key_name": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "route": {
+                    "description": "Route is the route pattern and Target the resource it addressed, with phone numbers masked",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BreakerState": {
+            "type": "string",
+            "enum": [
+                "closed",
+                "open",
+                "half-open"
+            ],
+            "x-enum-varnames": [
+                "BreakerClosed",
+                "BreakerOpen",
+                "BreakerHalfOpen"
+            ]
+        },
+        "domain.BreakerStatus": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "type": "integer"
+                },
+                "retry_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/domain.BreakerState"
+                },
+                "tenants": {
+                    "description": "Tenants holds the breakers of tenants that have sent messages, by tenant ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/domain.BreakerStatus"
+                    }
+                },
+                "trips": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CheckResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.HealthStatus"
+                }
+            }
+        },
+        "domain.ConfigReload": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "Applied lists the reloadable settings that changed and are now in effect",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "restart_required": {
+                    "description": "RestartRequired lists the sections that changed but only take effect after a restart",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.EncodingSummary": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "segments": {
+                    "type": "integer"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.HealthReport": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/domain.CheckResult"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.HealthStatus"
+                }
+            }
+        },
+        "domain.HealthStatus": {
+            "type": "string",
+            "enum": [
+                "up",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "HealthUp",
+                "HealthDown"
+            ]
+        },
+        "domain.InboundAction": {
+            "type": "string",
+            "enum": [
+                "opt_out",
+                "opt_in",
+                "none"
+            ],
+            "x-enum-varnames": [
+                "InboundOptOut",
+                "InboundOptIn",
+                "InboundIgnore"
+            ]
+        },
+        "domain.Message": {
+            "type": "object",
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category selects the quiet hours that apply; categories without a window are sent any time",
+                    "type": "string"
+                },
+                "concat_ref": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "country_code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dedupe_hash": {
+                    "description": "DedupeHash identifies the (recipient, content) pair; DuplicateOf is set when the\nmessage was collapsed into an identical one sent within the dedupe window",
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "duplicate_of": {
+                    "type": "integer"
+                },
+                "encoding": {
+                    "type": "string"
+                },
+                "failure_reason": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "part_number": {
+                    "type": "integer"
+                },
+                "part_total": {
+                    "type": "integer"
+                },
+                "parts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Message"
+                    }
+                },
+                "provider_message_id": {
+                    "type": "string"
+                },
+                "retry_count": {
+                    "type": "integer"
+                },
+                "segments": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.Status"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "template_version": {
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "description": "TenantID is the team the message belongs to; its settings override the app-wide ones",
+                    "type": "string"
+                },
+                "timezone": {
+                    "description": "Timezone is the recipient's IANA zone used for quiet hours",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "transliterate": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Recipient": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Role": {
+            "type": "string",
+            "enum": [
+                "read",
+                "send",
+                "operator",
+                "admin",
+                "send"
+            ],
+            "x-enum-varnames": [
+                "RoleRead",
+                "RoleSend",
+                "RoleOperator",
+                "RoleAdmin",
+                "MaxTenantRole"
+            ]
+        },
+        "domain.Schedule": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron is a standard five-field expression or a descriptor such as @weekly",
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "description": "NextRunAt is nil once the schedule has no occurrences left before EndAt",
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.SenderCounters": {
+            "type": "object",
+            "properties": {
+                "deferred": {
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "retried": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "suppressed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SenderStatus": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "type": "integer"
+                },
+                "counters": {
+                    "description": "Counters are reset every time the sender is started",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.SenderCounters"
+                        }
+                    ]
+                },
+                "interval_seconds": {
+                    "type": "integer"
+                },
+                "last_batch_size": {
+                    "type": "integer"
+                },
+                "last_tick_at": {
+                    "description": "LastTickAt is when the last pass over pending messages finished; LastBatchSize is how many it fetched",
+                    "type": "string"
+                },
+                "next_tick_at": {
+                    "type": "string"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Status": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "sent",
+                "failed",
+                "delivered",
+                "undelivered",
+                "suppressed",
+                "duplicate",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusSent",
+                "StatusFailed",
+                "StatusDelivered",
+                "StatusUndelivered",
+                "StatusSuppressed",
+                "StatusDuplicate",
+                "StatusCancelled"
+            ]
+        },
+        "domain.Suppression": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "$ref": "#/definitions/domain.SuppressionSource"
+                }
+            }
+        },
+        "domain.SuppressionSource": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "keyword"
+            ],
+            "x-enum-varnames": [
+                "SuppressionManual",
+                "SuppressionKeyword"
+            ]
+        },
+        "domain.Template": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TemplateVersion": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.TransliterationPreview": {
+            "type": "object",
+            "properties": {
+                "original": {
+                    "$ref": "#/definitions/domain.EncodingSummary"
+                },
+                "segments_saved": {
+                    "type": "integer"
+                },
+                "transliterated": {
+                    "$ref": "#/definitions/domain.EncodingSummary"
+                }
+            }
+        },
+        "handlers.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "role"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role is one of read, send, operator or admin; each includes the ones before it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Role"
+                        }
+                    ]
+                },
+                "tenant_id": {
+                    "description": "TenantID confines the key to a configured tenant, which allows the read and send roles",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "$ref": "#/definitions/domain.APIKey"
+                },
+                "secret": {
+                    "description": "Secret is the API key itself; only its hash is stored, so it can't be shown again",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.DeliveryReceiptRequest": {
+            "type": "object",
+            "required": [
+                "messageId",
+                "status"
+            ],
+            "properties": {
+                "errorCode": {
+                    "type": "string"
+                },
+                "messageId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.EnqueueMessageRequest": {
+            "type": "object",
+            "required": [
+                "to"
+            ],
+            "properties": {
+                "callback_url": {
+                    "type": "string"
+                },
+                "category": {
+                    "description": "Category selects quiet hours, e.g. marketing; Timezone overrides the recipient's IANA zone",
+                    "type": "string"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale picks the template variant; defaults to the recipient's stored preference",
+                    "type": "string"
+                },
+                "template_id": {
+                    "description": "TemplateID renders content from a template instead; TemplateVersion pins a version",
+                    "type": "integer"
+                },
+                "template_version": {
+                    "type": "integer"
+                },
+                "tenant_id": {
+                    "description": "TenantID sends for a configured tenant; tenant keys always send for their own",
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "transliterate": {
+                    "type": "boolean"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.FailResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.InboundMessageRequest": {
+            "type": "object",
+            "required": [
+                "from"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.InboundMessageResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/domain.InboundAction"
+                }
+            }
+        },
+        "handlers.PreviewRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RecipientPreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "locale": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SchedulePreviewResponse": {
+            "type": "object",
+            "properties": {
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.ScheduleRequest": {
+            "type": "object",
+            "required": [
+                "cron",
+                "name",
+                "recipients",
+                "template_id"
+            ],
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "cron": {
+                    "description": "Cron is a standard five-field expression (minute hour day month weekday) or a descriptor such as @weekly",
+                    "type": "string"
+                },
+                "end_at": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "start_at": {
+                    "type": "string"
+                },
+                "template_id": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "Timezone is the IANA zone the expression is evaluated in; defaults to UTC",
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.StartSenderRequest": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1
+                },
+                "interval_seconds": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 1
+                }
+            }
+        },
+        "handlers.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SuppressionRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "phone": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TemplateRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "name"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "Locale is the language of Body; defaults to the configured default locale",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type healthCheck struct {
+	client *redis.Client
+}
+
+// NewHealthCheck pings Redis
+func NewHealthCheck(client *redis.Client) ports.HealthCheck {
+	return &healthCheck{client: client}
+}
+
+func (h *healthCheck) Name() string {
+	return "redis"
+}
+
+func (h *healthCheck) Check(ctx context.Context) error {
+	return h.client.Ping(ctx).Err()
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	globalBucketKey    = "ratelimit:global"
+	recipientWindowKey = "ratelimit:recipient:"
+)
+
+// reserveScript checks the per-recipient window and the global token bucket and only
+// consumes from both when both have capacity. It returns 0 when the send may go ahead,
+// otherwise the number of milliseconds to wait. Redis server time is used so replicas
+// with skewed clocks still share one bucket.
+var reserveScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local recipientLimit = tonumber(ARGV[3])
+local windowMs = tonumber(ARGV[4])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+if recipientLimit > 0 then
+	local count = tonumber(redis.call('GET', KEYS[2]) or '0')
+	if count >= recipientLimit then
+		local ttl = redis.call('PTTL', KEYS[2])
+		if ttl < 0 then
+			ttl = windowMs
+		end
+		return ttl
+	end
+end
+
+if rate > 0 then
+	local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+	local tokens = tonumber(state[1]) or burst
+	local ts = tonumber(state[2]) or now
+	tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+	if tokens < 1 then
+		return math.ceil((1 - tokens) * 1000 / rate)
+	end
+	redis.call('HSET', KEYS[1], 'tokens', tokens - 1, 'ts', now)
+	redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+end
+
+if recipientLimit > 0 then
+	if redis.call('INCR', KEYS[2]) == 1 then
+		redis.call('PEXPIRE', KEYS[2], windowMs)
+	end
+end
+
+return 0
+`)
+
+type redisRateLimiter struct {
+	client          *redis.Client
+	globalPerSecond float64
+	globalBurst     int
+	recipientLimit  int
+	recipientWindow time.Duration
+}
+
+// NewRedisRateLimiter creates a limiter shared by every replica using the same Redis.
+// A zero globalPerSecond or recipientLimit disables that limit.
+func NewRedisRateLimiter(client *redis.Client, globalPerSecond float64, globalBurst int,
+	recipientLimit int, recipientWindow time.Duration) ports.RateLimiter {
+	if globalBurst <= 0 {
+		globalBurst = 1
+	}
+	if recipientWindow <= 0 {
+		recipientWindow = time.Minute
+	}
+
+	return &redisRateLimiter{
+		client:          client,
+		globalPerSecond: globalPerSecond,
+		globalBurst:     globalBurst,
+		recipientLimit:  recipientLimit,
+		recipientWindow: recipientWindow,
+	}
+}
+
+func (r *redisRateLimiter) Reserve(ctx context.Context, recipient string) (time.Duration, error) {
+	keys := []string{globalBucketKey, recipientWindowKey + recipient}
+	waitMs, err := reserveScript.Run(ctx, r.client, keys,
+		r.globalPerSecond, r.globalBurst, r.recipientLimit, r.recipientWindow.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(waitMs) * time.Millisecond, nil
+}
@@ -2,10 +2,15 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/hasElvin/messenger-svc/internal/core/ports"
 	"log"
+	"log/slog"
+	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -19,6 +24,9 @@ func InitRedis(cfg config.Config) *redis.Client {
 		log.Fatalf("Failed to parse Redis URL: %v", err)
 	}
 	client := redis.NewClient(opt)
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		log.Fatalf("Failed to instrument Redis: %v", err)
+	}
 
 	// Test Redis connection
 	_, err = client.Ping(context.Background()).Result()
@@ -26,7 +34,7 @@ func InitRedis(cfg config.Config) *redis.Client {
 		log.Fatalf("Failed to connect to Redis: %v", err)
 	}
 
-	log.Println("Redis connected successfully")
+	slog.Info("Redis connected successfully")
 	return client
 }
 
@@ -38,6 +46,22 @@ func (r *redisCache) Set(ctx context.Context, key, value string) error {
 	return r.client.Set(ctx, key, value, 0).Err()
 }
 
+func (r *redisCache) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (r *redisCache) SetIfAbsent(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 func (r *redisCache) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	value, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", domain.ErrCacheMiss
+	}
+	return value, err
+}
+
+func (r *redisCache) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
 }
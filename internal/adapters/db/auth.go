@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type APIKeyModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"not null"`
+	Prefix     string `gorm:"not null"`
+	Hash       string `gorm:"uniqueIndex;not null"`
+	Role       string `gorm:"not null"`
+	TenantID   string `gorm:"index"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+func (APIKeyModel) TableName() string {
+	return "api_keys"
+}
+
+type AuditModel struct {
+	ID        uint  `gorm:"primaryKey"`
+	APIKeyID  *uint `gorm:"index"`
+	KeyName   string
+	Method    string `gorm:"not null"`
+	Route     string `gorm:"not null"`
+	Target    string
+	Status    int
+	RequestID string
+	ClientIP  string
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (AuditModel) TableName() string {
+	return "audit_log"
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) ports.APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	model := APIKeyModel{
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Hash:      key.Hash,
+		Role:      string(key.Role),
+		TenantID:  key.TenantID,
+		ExpiresAt: key.ExpiresAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return err
+	}
+
+	*key = r.toDomain(model)
+	return nil
+}
+
+func (r *apiKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	var model APIKeyModel
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := r.toDomain(model)
+	return &key, nil
+}
+
+func (r *apiKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	var models []APIKeyModel
+	if err := r.db.WithContext(ctx).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.APIKey, len(models))
+	for i, model := range models {
+		keys[i] = r.toDomain(model)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey keeps the first revocation time if the key is revoked twice
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&APIKeyModel{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&APIKeyModel{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&APIKeyModel{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+func (r *apiKeyRepository) toDomain(model APIKeyModel) domain.APIKey {
+	return domain.APIKey{
+		ID:         model.ID,
+		Name:       model.Name,
+		Prefix:     model.Prefix,
+		Hash:       model.Hash,
+		Role:       domain.Role(model.Role),
+		TenantID:   model.TenantID,
+		ExpiresAt:  model.ExpiresAt,
+		LastUsedAt: model.LastUsedAt,
+		RevokedAt:  model.RevokedAt,
+		CreatedAt:  model.CreatedAt,
+	}
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) ports.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) RecordAudit(ctx context.Context, entry *domain.AuditEntry) error {
+	model := AuditModel{
+		APIKeyID:  entry.APIKeyID,
+		KeyName:   entry.KeyName,
+		Method:    entry.Method,
+		Route:     entry.Route,
+		Target:    entry.Target,
+		Status:    entry.Status,
+		RequestID: entry.RequestID,
+		ClientIP:  entry.ClientIP,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return err
+	}
+
+	entry.ID = model.ID
+	entry.CreatedAt = model.CreatedAt
+	return nil
+}
+
+// ListAudit returns the newest entries first
+func (r *auditRepository) ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	query := r.db.WithContext(ctx).Order("id DESC").Limit(filter.Limit)
+	if filter.APIKeyID != 0 {
+		query = query.Where("api_key_id = ?", filter.APIKeyID)
+	}
+
+	var models []AuditModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.AuditEntry, len(models))
+	for i, model := range models {
+		entries[i] = domain.AuditEntry{
+			ID:        model.ID,
+			APIKeyID:  model.APIKeyID,
+			KeyName:   model.KeyName,
+			Method:    model.Method,
+			Route:     model.Route,
+			Target:    model.Target,
+			Status:    model.Status,
+			RequestID: model.RequestID,
+			ClientIP:  model.ClientIP,
+			CreatedAt: model.CreatedAt,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type CallbackModel struct {
+	ID            uint      `gorm:"primaryKey"`
+	MessageID     uint      `gorm:"not null;index"`
+	URL           string    `gorm:"not null"`
+	Payload       []byte    `gorm:"not null"`
+	Attempts      int       `gorm:"default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	DeliveredAt   *time.Time
+	AbandonedAt   *time.Time
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func (CallbackModel) TableName() string {
+	return "status_callbacks"
+}
+
+type callbackRepository struct {
+	db *gorm.DB
+}
+
+func NewCallbackRepository(db *gorm.DB) ports.CallbackRepository {
+	return &callbackRepository{db: db}
+}
+
+func (r *callbackRepository) EnqueueCallback(ctx context.Context, callback *domain.StatusCallback) error {
+	model := CallbackModel{
+		MessageID:     callback.MessageID,
+		URL:           callback.URL,
+		Payload:       callback.Payload,
+		NextAttemptAt: callback.NextAttemptAt,
+	}
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return err
+	}
+
+	callback.ID = model.ID
+	callback.CreatedAt = model.CreatedAt
+	return nil
+}
+
+func (r *callbackRepository) ClaimDueCallbacks(ctx context.Context,
+	limit int, lease time.Duration) ([]domain.StatusCallback, error) {
+
+	now := time.Now()
+	var models []CallbackModel
+
+	// SKIP LOCKED lets replicas claim disjoint batches; pushing next_attempt_at forward
+	// is the lease, so a crashed worker's callbacks become due again on their own
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE status_callbacks
+		SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM status_callbacks
+			WHERE delivered_at IS NULL AND abandoned_at IS NULL AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, now, limit).
+		Scan(&models).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	callbacks := make([]domain.StatusCallback, len(models))
+	for i, model := range models {
+		callbacks[i] = r.toDomain(model)
+	}
+
+	return callbacks, nil
+}
+
+func (r *callbackRepository) MarkCallbackDelivered(ctx context.Context, id uint) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&CallbackModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"delivered_at": now,
+			"last_error":   "",
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *callbackRepository) RescheduleCallback(ctx context.Context, id uint, next time.Time, lastErr string) error {
+	return r.db.WithContext(ctx).
+		Model(&CallbackModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"next_attempt_at": next,
+			"last_error":      lastErr,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func (r *callbackRepository) MarkCallbackAbandoned(ctx context.Context, id uint, lastErr string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).
+		Model(&CallbackModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"abandoned_at": now,
+			"last_error":   lastErr,
+			"updated_at":   now,
+		}).Error
+}
+
+func (r *callbackRepository) toDomain(model CallbackModel) domain.StatusCallback {
+	return domain.StatusCallback{
+		ID:            model.ID,
+		MessageID:     model.MessageID,
+		URL:           model.URL,
+		Payload:       model.Payload,
+		Attempts:      model.Attempts,
+		NextAttemptAt: model.NextAttemptAt,
+		DeliveredAt:   model.DeliveredAt,
+		AbandonedAt:   model.AbandonedAt,
+		LastError:     model.LastError,
+		CreatedAt:     model.CreatedAt,
+	}
+}
@@ -0,0 +1,28 @@
+package db
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"gorm.io/gorm"
+)
+
+type healthCheck struct {
+	db *gorm.DB
+}
+
+// NewHealthCheck pings the database connection pool
+func NewHealthCheck(db *gorm.DB) ports.HealthCheck {
+	return &healthCheck{db: db}
+}
+
+func (h *healthCheck) Name() string {
+	return "postgres"
+}
+
+func (h *healthCheck) Check(ctx context.Context) error {
+	sqlDB, err := h.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
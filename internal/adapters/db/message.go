@@ -2,20 +2,45 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"gorm.io/gorm"
 	"time"
 )
 
 type MessageModel struct {
-	ID         uint   `gorm:"primaryKey"`
-	To         string `gorm:"not null"`
-	Content    string `gorm:"not null;size:160"`
-	Status     string `gorm:"default:'pending'"`
-	RetryCount int    `gorm:"default:0"`
-	SentAt     *time.Time
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID                uint   `gorm:"primaryKey"`
+	TenantID          string `gorm:"index"`
+	To                string `gorm:"not null"`
+	CountryCode       string `gorm:"size:2;index"`
+	Content           string `gorm:"not null;type:text"`
+	Status            string `gorm:"default:'pending'"`
+	RetryCount        int    `gorm:"default:0"`
+	SentAt            *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	NextAttemptAt     *time.Time `gorm:"index"`
+	ProviderMessageID string     `gorm:"index"`
+	DeliveredAt       *time.Time
+	CallbackURL       string
+	Encoding          string
+	Segments          int
+	FailureReason     string
+	Transliterate     bool
+	ParentID          *uint `gorm:"index"`
+	PartNumber        int
+	PartTotal         int
+	ConcatRef         int
+	Parts             []MessageModel `gorm:"foreignKey:ParentID"`
+	TemplateID        uint           `gorm:"index"`
+	TemplateVersion   int
+	Locale            string
+	Category          string
+	Timezone          string
+	DedupeHash        string `gorm:"index"`
+	DuplicateOf       *uint
+	TraceParent       string
 }
 
 func (MessageModel) TableName() string {
@@ -23,12 +48,14 @@ func (MessageModel) TableName() string {
 }
 
 func (r *postgresRepository) GetPendingMessages(ctx context.Context,
-	limit, messageCharLimit, maxRetries int) ([]domain.Message, error) {
+	limit, maxRetries int) ([]domain.Message, error) {
 
 	var models []MessageModel
 	err := r.db.WithContext(ctx).
-		Where("status = ? AND char_length(content) <= ? AND retry_count < ?",
-			domain.StatusPending, messageCharLimit, maxRetries).
+		Where("status = ? AND retry_count < ? AND parent_id IS NULL", string(domain.StatusPending), maxRetries).
+		Where("next_attempt_at IS NULL OR next_attempt_at <= ?", time.Now()).
+		Preload("Parts", orderedParts).
+		Order("id").
 		Limit(limit).
 		Find(&models).Error
 
@@ -44,27 +71,107 @@ func (r *postgresRepository) GetPendingMessages(ctx context.Context,
 	return messages, nil
 }
 
-func (r *postgresRepository) UpdateMessageStatus(ctx context.Context, id uint, status string) error {
-	now := time.Now()
+func (r *postgresRepository) CountPendingMessages(ctx context.Context, maxRetries int) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&MessageModel{}).
+		Where("status = ? AND retry_count < ? AND parent_id IS NULL", string(domain.StatusPending), maxRetries).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *postgresRepository) UpdateMessageStatus(ctx context.Context, msg domain.Message, from domain.Status) error {
+	if !from.CanTransitionTo(msg.Status) {
+		return fmt.Errorf("%w: message %d can't move from %s to %s",
+			domain.ErrInvalidTransition, msg.ID, from, msg.Status)
+	}
+
 	updates := map[string]interface{}{
-		"status":     status,
-		"updated_at": now,
+		"status":     string(msg.Status),
+		"updated_at": time.Now(),
 	}
 
-	if status == domain.StatusSent {
-		updates["sent_at"] = now
+	switch msg.Status {
+	case domain.StatusSent:
+		updates["sent_at"] = msg.SentAt
+		updates["provider_message_id"] = msg.ProviderMessageID
+	case domain.StatusFailed:
+		updates["failure_reason"] = msg.FailureReason
+	case domain.StatusDelivered:
+		updates["delivered_at"] = msg.DeliveredAt
+	case domain.StatusUndelivered:
+		updates["delivered_at"] = msg.DeliveredAt
+		updates["failure_reason"] = msg.FailureReason
+	case domain.StatusDuplicate:
+		updates["duplicate_of"] = msg.DuplicateOf
 	}
 
-	return r.db.WithContext(ctx).
+	// Conditional on the status we read, so a late writer can't overwrite a newer status
+	result := r.db.WithContext(ctx).
 		Model(&MessageModel{}).
-		Where("id = ?", id).
-		Updates(updates).Error
+		Where("id = ? AND status = ?", msg.ID, string(from)).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: message %d is no longer %s", domain.ErrStatusConflict, msg.ID, from)
+	}
+
+	return nil
 }
 
-func (r *postgresRepository) GetSentMessages(ctx context.Context) ([]domain.Message, error) {
-	var models []MessageModel
+func (r *postgresRepository) GetMessageByProviderID(ctx context.Context,
+	providerMessageID string) (*domain.Message, error) {
+
+	var model MessageModel
+	err := r.db.WithContext(ctx).
+		Where("provider_message_id = ?", providerMessageID).
+		Preload("Parts", orderedParts).
+		Order("parent_id IS NULL DESC, id DESC").
+		First(&model).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message := r.toDomain(model)
+	return &message, nil
+}
+
+func (r *postgresRepository) GetMessage(ctx context.Context, id uint) (*domain.Message, error) {
+	var model MessageModel
 	err := r.db.WithContext(ctx).
-		Where("status = ?", domain.StatusSent).
+		Where("id = ? AND parent_id IS NULL", id).
+		Preload("Parts", orderedParts).
+		First(&model).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message := r.toDomain(model)
+	return &message, nil
+}
+
+func (r *postgresRepository) GetSentMessages(ctx context.Context, tenantID string) ([]domain.Message, error) {
+	query := r.db.WithContext(ctx).
+		Where("status IN ? AND parent_id IS NULL", []string{string(domain.StatusSent),
+			string(domain.StatusDelivered), string(domain.StatusUndelivered)})
+	if tenantID != "" {
+		query = query.Where("tenant_id = ?", tenantID)
+	}
+
+	var models []MessageModel
+	err := query.
+		Preload("Parts", orderedParts).
 		Find(&models).Error
 
 	if err != nil {
@@ -89,6 +196,12 @@ func (r *postgresRepository) CreateMessage(ctx context.Context, message *domain.
 	message.ID = model.ID
 	message.CreatedAt = model.CreatedAt
 	message.UpdatedAt = model.UpdatedAt
+	for i := range message.Parts {
+		message.Parts[i].ID = model.Parts[i].ID
+		message.Parts[i].ParentID = model.Parts[i].ParentID
+		message.Parts[i].CreatedAt = model.Parts[i].CreatedAt
+		message.Parts[i].UpdatedAt = model.Parts[i].UpdatedAt
+	}
 
 	return nil
 }
@@ -100,28 +213,96 @@ func (r *postgresRepository) IncrementRetryCount(ctx context.Context, id uint) e
 		Update("retry_count", gorm.Expr("retry_count + 1")).Error
 }
 
+func (r *postgresRepository) DeferMessage(ctx context.Context, id uint, until time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&MessageModel{}).
+		Where("id = ?", id).
+		Update("next_attempt_at", until).Error
+}
+
 func (r *postgresRepository) toDomain(model MessageModel) domain.Message {
-	return domain.Message{
-		ID:         model.ID,
-		To:         model.To,
-		Content:    model.Content,
-		Status:     model.Status,
-		SentAt:     model.SentAt,
-		CreatedAt:  model.CreatedAt,
-		UpdatedAt:  model.UpdatedAt,
-		RetryCount: model.RetryCount,
+	message := domain.Message{
+		ID:                model.ID,
+		TenantID:          model.TenantID,
+		To:                model.To,
+		Content:           model.Content,
+		Status:            domain.Status(model.Status),
+		SentAt:            model.SentAt,
+		CreatedAt:         model.CreatedAt,
+		UpdatedAt:         model.UpdatedAt,
+		RetryCount:        model.RetryCount,
+		NextAttemptAt:     model.NextAttemptAt,
+		ProviderMessageID: model.ProviderMessageID,
+		DeliveredAt:       model.DeliveredAt,
+		CallbackURL:       model.CallbackURL,
+		Encoding:          model.Encoding,
+		Segments:          model.Segments,
+		FailureReason:     model.FailureReason,
+		Transliterate:     model.Transliterate,
+		CountryCode:       model.CountryCode,
+		TemplateID:        model.TemplateID,
+		TemplateVersion:   model.TemplateVersion,
+		Locale:            model.Locale,
+		Category:          model.Category,
+		Timezone:          model.Timezone,
+		DedupeHash:        model.DedupeHash,
+		DuplicateOf:       model.DuplicateOf,
+		TraceParent:       model.TraceParent,
+		ParentID:          model.ParentID,
+		PartNumber:        model.PartNumber,
+		PartTotal:         model.PartTotal,
+		ConcatRef:         model.ConcatRef,
+	}
+
+	for _, part := range model.Parts {
+		message.Parts = append(message.Parts, r.toDomain(part))
 	}
+
+	return message
 }
 
 func (r *postgresRepository) toModel(message domain.Message) MessageModel {
-	return MessageModel{
-		ID:         message.ID,
-		To:         message.To,
-		Content:    message.Content,
-		Status:     message.Status,
-		SentAt:     message.SentAt,
-		CreatedAt:  message.CreatedAt,
-		UpdatedAt:  message.UpdatedAt,
-		RetryCount: message.RetryCount,
+	model := MessageModel{
+		ID:                message.ID,
+		TenantID:          message.TenantID,
+		To:                message.To,
+		Content:           message.Content,
+		Status:            string(message.Status),
+		SentAt:            message.SentAt,
+		CreatedAt:         message.CreatedAt,
+		UpdatedAt:         message.UpdatedAt,
+		RetryCount:        message.RetryCount,
+		NextAttemptAt:     message.NextAttemptAt,
+		ProviderMessageID: message.ProviderMessageID,
+		DeliveredAt:       message.DeliveredAt,
+		CallbackURL:       message.CallbackURL,
+		Encoding:          message.Encoding,
+		Segments:          message.Segments,
+		FailureReason:     message.FailureReason,
+		Transliterate:     message.Transliterate,
+		CountryCode:       message.CountryCode,
+		TemplateID:        message.TemplateID,
+		TemplateVersion:   message.TemplateVersion,
+		Locale:            message.Locale,
+		Category:          message.Category,
+		Timezone:          message.Timezone,
+		DedupeHash:        message.DedupeHash,
+		DuplicateOf:       message.DuplicateOf,
+		TraceParent:       message.TraceParent,
+		ParentID:          message.ParentID,
+		PartNumber:        message.PartNumber,
+		PartTotal:         message.PartTotal,
+		ConcatRef:         message.ConcatRef,
+	}
+
+	for _, part := range message.Parts {
+		model.Parts = append(model.Parts, r.toModel(part))
 	}
+
+	return model
+}
+
+// orderedParts preloads multipart children in the order they must be sent
+func orderedParts(db *gorm.DB) *gorm.DB {
+	return db.Order("part_number")
 }
@@ -6,7 +6,9 @@ import (
 	"github.com/hasElvin/messenger-svc/internal/core/ports"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
 	"log"
+	"log/slog"
 )
 
 type postgresRepository struct {
@@ -21,20 +23,44 @@ func InitPostgres(cfg config.Config) *gorm.DB {
 		cfg.Database.Name, cfg.Database.SSLMode,
 	)
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: newGormLogger()})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Auto-migrate the message table
-	if err := database.AutoMigrate(&MessageModel{}); err != nil {
+	// Query variables hold recipients and message bodies, so they stay out of spans
+	if err := database.Use(tracing.NewPlugin(tracing.WithoutQueryVariables(), tracing.WithoutMetrics())); err != nil {
+		log.Fatalf("Failed to instrument database: %v", err)
+	}
+
+	// Auto-migrate the message, status callback, template, recipient, suppression, schedule,
+	// API key and audit tables
+	if err := database.AutoMigrate(&MessageModel{}, &CallbackModel{}, &TemplateModel{},
+		&TemplateVersionModel{}, &RecipientModel{}, &SuppressionModel{}, &ScheduleModel{},
+		&APIKeyModel{}, &AuditModel{}); err != nil {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
-	log.Println("Database connected and migrated successfully")
+	// Template versions became unique per locale; the old per-version index would reject variants
+	if database.Migrator().HasIndex(&TemplateVersionModel{}, "idx_template_versions_version") {
+		if err := database.Migrator().DropIndex(&TemplateVersionModel{}, "idx_template_versions_version"); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+	}
+
+	slog.Info("Database connected and migrated successfully")
 	return database
 }
 
+// ClosePostgres closes the connection pool once nothing is using the database any more
+func ClosePostgres(database *gorm.DB) error {
+	sqlDB, err := database.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
 func NewPostgresRepository(db *gorm.DB) ports.MessageRepository {
 	return &postgresRepository{db: db}
 }
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type RecipientModel struct {
+	Phone     string `gorm:"primaryKey"`
+	Locale    string
+	Timezone  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (RecipientModel) TableName() string {
+	return "recipients"
+}
+
+type recipientRepository struct {
+	db *gorm.DB
+}
+
+func NewRecipientRepository(db *gorm.DB) ports.RecipientRepository {
+	return &recipientRepository{db: db}
+}
+
+func (r *recipientRepository) GetRecipient(ctx context.Context, phone string) (*domain.Recipient, error) {
+	var model RecipientModel
+	err := r.db.WithContext(ctx).Where("phone = ?", phone).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrRecipientNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toDomain(model), nil
+}
+
+func (r *recipientRepository) UpsertRecipient(ctx context.Context, recipient *domain.Recipient) error {
+	model := RecipientModel{
+		Phone:    recipient.Phone,
+		Locale:   recipient.Locale,
+		Timezone: recipient.Timezone,
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "phone"}},
+		DoUpdates: clause.AssignmentColumns([]string{"locale", "timezone", "updated_at"}),
+	}).Create(&model).Error
+	if err != nil {
+		return err
+	}
+
+	// The insert's timestamps are wrong when the row already existed, so read them back
+	if err := r.db.WithContext(ctx).Where("phone = ?", recipient.Phone).First(&model).Error; err != nil {
+		return err
+	}
+
+	*recipient = *r.toDomain(model)
+	return nil
+}
+
+func (r *recipientRepository) toDomain(model RecipientModel) *domain.Recipient {
+	return &domain.Recipient{
+		Phone:     model.Phone,
+		Locale:    model.Locale,
+		Timezone:  model.Timezone,
+		CreatedAt: model.CreatedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"gorm.io/gorm"
+	"time"
+)
+
+type ScheduleModel struct {
+	ID         uint              `gorm:"primaryKey"`
+	Name       string            `gorm:"not null"`
+	Cron       string            `gorm:"not null"`
+	Timezone   string            `gorm:"not null;default:'UTC'"`
+	TemplateID uint              `gorm:"not null;index"`
+	Variables  map[string]string `gorm:"serializer:json;type:jsonb"`
+	Locale     string
+	Category   string
+	Recipients []string `gorm:"serializer:json;type:jsonb;not null"`
+	StartAt    *time.Time
+	EndAt      *time.Time
+	NextRunAt  *time.Time `gorm:"index"`
+	LastRunAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (ScheduleModel) TableName() string {
+	return "schedules"
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ports.ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+func (r *scheduleRepository) CreateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	model := r.toModel(*schedule)
+	if err := r.db.WithContext(ctx).Create(&model).Error; err != nil {
+		return err
+	}
+
+	*schedule = r.toDomain(model)
+	return nil
+}
+
+func (r *scheduleRepository) GetSchedule(ctx context.Context, id uint) (*domain.Schedule, error) {
+	var model ScheduleModel
+	err := r.db.WithContext(ctx).First(&model, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := r.toDomain(model)
+	return &schedule, nil
+}
+
+func (r *scheduleRepository) ListSchedules(ctx context.Context) ([]domain.Schedule, error) {
+	var models []ScheduleModel
+	if err := r.db.WithContext(ctx).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	schedules := make([]domain.Schedule, len(models))
+	for i, model := range models {
+		schedules[i] = r.toDomain(model)
+	}
+	return schedules, nil
+}
+
+func (r *scheduleRepository) UpdateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	model := r.toModel(*schedule)
+	result := r.db.WithContext(ctx).
+		Model(&ScheduleModel{ID: schedule.ID}).
+		Select("name", "cron", "timezone", "template_id", "variables", "locale", "category",
+			"recipients", "start_at", "end_at", "next_run_at", "updated_at").
+		Updates(&model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrScheduleNotFound
+	}
+
+	stored, err := r.GetSchedule(ctx, schedule.ID)
+	if err != nil {
+		return err
+	}
+	*schedule = *stored
+	return nil
+}
+
+func (r *scheduleRepository) DeleteSchedule(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&ScheduleModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *scheduleRepository) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]domain.Schedule, error) {
+	var models []ScheduleModel
+	err := r.db.WithContext(ctx).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make([]domain.Schedule, len(models))
+	for i, model := range models {
+		schedules[i] = r.toDomain(model)
+	}
+	return schedules, nil
+}
+
+func (r *scheduleRepository) AdvanceSchedule(ctx context.Context, id uint, due time.Time, next *time.Time) (bool, error) {
+	// Conditional on next_run_at so concurrent replicas can't both claim the same occurrence
+	result := r.db.WithContext(ctx).
+		Model(&ScheduleModel{}).
+		Where("id = ? AND next_run_at = ?", id, due).
+		Updates(map[string]interface{}{
+			"next_run_at": next,
+			"last_run_at": due,
+			"updated_at":  time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *scheduleRepository) toModel(schedule domain.Schedule) ScheduleModel {
+	return ScheduleModel{
+		ID:         schedule.ID,
+		Name:       schedule.Name,
+		Cron:       schedule.Cron,
+		Timezone:   schedule.Timezone,
+		TemplateID: schedule.TemplateID,
+		Variables:  schedule.Variables,
+		Locale:     schedule.Locale,
+		Category:   schedule.Category,
+		Recipients: schedule.Recipients,
+		StartAt:    schedule.StartAt,
+		EndAt:      schedule.EndAt,
+		NextRunAt:  schedule.NextRunAt,
+		LastRunAt:  schedule.LastRunAt,
+	}
+}
+
+func (r *scheduleRepository) toDomain(model ScheduleModel) domain.Schedule {
+	return domain.Schedule{
+		ID:         model.ID,
+		Name:       model.Name,
+		Cron:       model.Cron,
+		Timezone:   model.Timezone,
+		TemplateID: model.TemplateID,
+		Variables:  model.Variables,
+		Locale:     model.Locale,
+		Category:   model.Category,
+		Recipients: model.Recipients,
+		StartAt:    model.StartAt,
+		EndAt:      model.EndAt,
+		NextRunAt:  model.NextRunAt,
+		LastRunAt:  model.LastRunAt,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+	}
+}
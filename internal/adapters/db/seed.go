@@ -1,7 +1,7 @@
 package db
 
 import (
-	"log"
+	"log/slog"
 )
 
 // SeedSampleMessages inserts sample data for testing
@@ -20,20 +20,20 @@ func (r *postgresRepository) SeedSampleMessages() error {
 	}
 
 	if err := r.db.Create(&messages).Error; err != nil {
-		log.Printf("Failed to seed sample messages: %v", err)
+		slog.Error("Failed to seed sample messages", "error", err)
 		return err
 	}
 
-	log.Println("10 sample messages inserted into the database")
+	slog.Info("Sample messages inserted into the database", "count", len(messages))
 	return nil
 }
 
 // ClearDatabase truncates the messages table to clear all data
 func (r *postgresRepository) ClearDatabase() error {
 	if err := r.db.Exec("TRUNCATE TABLE messages").Error; err != nil {
-		log.Printf("Failed to clear database: %v", err)
+		slog.Error("Failed to clear database", "error", err)
 		return err
 	}
-	log.Println("Database cleared successfully")
+	slog.Info("Database cleared successfully")
 	return nil
 }
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type SuppressionModel struct {
+	Phone     string `gorm:"primaryKey"`
+	Source    string `gorm:"not null"`
+	Reason    string
+	CreatedAt time.Time
+}
+
+func (SuppressionModel) TableName() string {
+	return "suppressions"
+}
+
+type suppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewSuppressionRepository(db *gorm.DB) ports.SuppressionRepository {
+	return &suppressionRepository{db: db}
+}
+
+func (r *suppressionRepository) AddSuppression(ctx context.Context, suppression *domain.Suppression) error {
+	model := SuppressionModel{
+		Phone:  suppression.Phone,
+		Source: string(suppression.Source),
+		Reason: suppression.Reason,
+	}
+
+	// The first opt-out is the one that counts for compliance, so never overwrite it
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model).Error
+	if err != nil {
+		return err
+	}
+
+	stored, err := r.GetSuppression(ctx, suppression.Phone)
+	if err != nil {
+		return err
+	}
+	*suppression = *stored
+	return nil
+}
+
+func (r *suppressionRepository) GetSuppression(ctx context.Context, phone string) (*domain.Suppression, error) {
+	var model SuppressionModel
+	err := r.db.WithContext(ctx).Where("phone = ?", phone).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSuppressionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suppression := r.toDomain(model)
+	return &suppression, nil
+}
+
+func (r *suppressionRepository) RemoveSuppression(ctx context.Context, phone string) error {
+	result := r.db.WithContext(ctx).Where("phone = ?", phone).Delete(&SuppressionModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSuppressionNotFound
+	}
+	return nil
+}
+
+func (r *suppressionRepository) ListSuppressions(ctx context.Context) ([]domain.Suppression, error) {
+	var models []SuppressionModel
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	suppressions := make([]domain.Suppression, len(models))
+	for i, model := range models {
+		suppressions[i] = r.toDomain(model)
+	}
+	return suppressions, nil
+}
+
+func (r *suppressionRepository) toDomain(model SuppressionModel) domain.Suppression {
+	return domain.Suppression{
+		Phone:     model.Phone,
+		Source:    domain.SuppressionSource(model.Source),
+		Reason:    model.Reason,
+		CreatedAt: model.CreatedAt,
+	}
+}
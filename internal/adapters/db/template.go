@@ -0,0 +1,297 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const uniqueViolation = "23505"
+
+type TemplateModel struct {
+	ID             uint   `gorm:"primaryKey"`
+	Name           string `gorm:"not null;uniqueIndex:idx_templates_name,where:deleted_at IS NULL"`
+	Description    string
+	Locale         string `gorm:"not null;default:'en'"`
+	CurrentVersion int    `gorm:"not null;default:1"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+}
+
+func (TemplateModel) TableName() string {
+	return "templates"
+}
+
+// TemplateVersionModel holds one locale's body of one template version. Rows are never
+// updated, so messages can always be traced back to the exact text they were rendered from
+type TemplateVersionModel struct {
+	ID         uint   `gorm:"primaryKey"`
+	TemplateID uint   `gorm:"not null;uniqueIndex:idx_template_versions_locale"`
+	Version    int    `gorm:"not null;uniqueIndex:idx_template_versions_locale"`
+	Locale     string `gorm:"not null;default:'en';uniqueIndex:idx_template_versions_locale"`
+	Body       string `gorm:"not null;type:text"`
+	CreatedAt  time.Time
+}
+
+func (TemplateVersionModel) TableName() string {
+	return "template_versions"
+}
+
+type templateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) ports.TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) CreateTemplate(ctx context.Context, template *domain.Template) error {
+	model := TemplateModel{
+		Name:           template.Name,
+		Description:    template.Description,
+		Locale:         template.Locale,
+		CurrentVersion: 1,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		return tx.Create(versionRows(model.ID, 1, template)).Error
+	})
+	if err != nil {
+		return translateTemplateError(err, template.Name)
+	}
+
+	template.ID = model.ID
+	template.Version = model.CurrentVersion
+	template.CreatedAt = model.CreatedAt
+	template.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *templateRepository) GetTemplate(ctx context.Context, id uint, version int) (*domain.Template, error) {
+	var model TemplateModel
+	if err := r.db.WithContext(ctx).First(&model, id).Error; err != nil {
+		return nil, templateNotFound(err)
+	}
+
+	if version == 0 {
+		version = model.CurrentVersion
+	}
+
+	var rows []TemplateVersionModel
+	err := r.db.WithContext(ctx).
+		Where("template_id = ? AND version = ?", id, version).
+		Order("id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, domain.ErrTemplateNotFound
+	}
+
+	return r.toDomain(model, version, rows)
+}
+
+func (r *templateRepository) ListTemplates(ctx context.Context) ([]domain.Template, error) {
+	var models []TemplateModel
+	if err := r.db.WithContext(ctx).Order("name").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return []domain.Template{}, nil
+	}
+
+	ids := make([]uint, len(models))
+	for i, model := range models {
+		ids[i] = model.ID
+	}
+
+	// One query for the current bodies of every template
+	var versions []TemplateVersionModel
+	err := r.db.WithContext(ctx).
+		Joins("JOIN templates ON templates.id = template_versions.template_id AND templates.current_version = template_versions.version").
+		Where("template_versions.template_id IN ?", ids).
+		Order("template_versions.id").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uint][]TemplateVersionModel, len(models))
+	for _, version := range versions {
+		current[version.TemplateID] = append(current[version.TemplateID], version)
+	}
+
+	templates := make([]domain.Template, 0, len(models))
+	for _, model := range models {
+		template, err := r.toDomain(model, model.CurrentVersion, current[model.ID])
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+func (r *templateRepository) UpdateTemplate(ctx context.Context, template *domain.Template) error {
+	var model TemplateModel
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the template so concurrent edits get consecutive version numbers
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model, template.ID).Error
+		if err != nil {
+			return templateNotFound(err)
+		}
+
+		var current []TemplateVersionModel
+		err = tx.Where("template_id = ? AND version = ?", model.ID, model.CurrentVersion).Find(&current).Error
+		if err != nil {
+			return err
+		}
+
+		if model.Locale != template.Locale || !sameBodies(current, template) {
+			model.CurrentVersion++
+			if err := tx.Create(versionRows(model.ID, model.CurrentVersion, template)).Error; err != nil {
+				return err
+			}
+		}
+
+		model.Locale = template.Locale
+		model.Name = template.Name
+		model.Description = template.Description
+		return tx.Save(&model).Error
+	})
+	if err != nil {
+		return translateTemplateError(err, template.Name)
+	}
+
+	template.Version = model.CurrentVersion
+	template.CreatedAt = model.CreatedAt
+	template.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *templateRepository) DeleteTemplate(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&TemplateModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (r *templateRepository) ListTemplateVersions(ctx context.Context, id uint) ([]domain.TemplateVersion, error) {
+	var model TemplateModel
+	if err := r.db.WithContext(ctx).First(&model, id).Error; err != nil {
+		return nil, templateNotFound(err)
+	}
+
+	var models []TemplateVersionModel
+	err := r.db.WithContext(ctx).Where("template_id = ?", id).Order("version DESC, locale").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]domain.TemplateVersion, len(models))
+	for i, version := range models {
+		versions[i] = domain.TemplateVersion{
+			TemplateID: version.TemplateID,
+			Version:    version.Version,
+			Locale:     version.Locale,
+			Body:       version.Body,
+			CreatedAt:  version.CreatedAt,
+		}
+	}
+	return versions, nil
+}
+
+func (r *templateRepository) toDomain(model TemplateModel, version int,
+	rows []TemplateVersionModel) (*domain.Template, error) {
+
+	template := &domain.Template{
+		ID:          model.ID,
+		Name:        model.Name,
+		Description: model.Description,
+		Version:     version,
+		Locale:      model.Locale,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+
+	for _, row := range rows {
+		if row.Locale == model.Locale {
+			template.Body = row.Body
+			continue
+		}
+		if template.Variants == nil {
+			template.Variants = make(map[string]string)
+		}
+		template.Variants[row.Locale] = row.Body
+	}
+
+	// Older versions may predate a change of the default locale. Rows come in insertion order
+	// and versionRows writes a version's own default locale first.
+	if template.Body == "" && len(rows) > 0 {
+		template.Locale, template.Body = rows[0].Locale, rows[0].Body
+		delete(template.Variants, rows[0].Locale)
+	}
+
+	variables, err := domain.ParsePlaceholders(template.Body)
+	if err != nil {
+		return nil, err
+	}
+	template.Variables = variables
+	return template, nil
+}
+
+// versionRows lays a template's default body and variants out as rows of one version
+func versionRows(templateID uint, version int, template *domain.Template) []TemplateVersionModel {
+	rows := []TemplateVersionModel{{TemplateID: templateID, Version: version, Locale: template.Locale, Body: template.Body}}
+	for locale, body := range template.Variants {
+		rows = append(rows, TemplateVersionModel{TemplateID: templateID, Version: version, Locale: locale, Body: body})
+	}
+	return rows
+}
+
+func sameBodies(rows []TemplateVersionModel, template *domain.Template) bool {
+	if len(rows) != len(template.Variants)+1 {
+		return false
+	}
+	for _, row := range rows {
+		body, ok := template.Variants[row.Locale]
+		if row.Locale == template.Locale {
+			body, ok = template.Body, true
+		}
+		if !ok || body != row.Body {
+			return false
+		}
+	}
+	return true
+}
+
+func templateNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrTemplateNotFound
+	}
+	return err
+}
+
+func translateTemplateError(err error, name string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: a template named %q already exists", domain.ErrInvalidTemplate, name)
+	}
+	return err
+}
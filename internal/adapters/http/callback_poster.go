@@ -0,0 +1,77 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/netguard"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type callbackPoster struct {
+	secret string
+	client *http.Client
+}
+
+// NewCallbackPoster signs each callback with sign and sends the Unix timestamp it signed in
+// X-Signature-Timestamp.
+// Callback URLs come from API clients, so connections to internal addresses are refused
+// whatever the URL's host resolves to, including on redirects.
+func NewCallbackPoster(secret string) ports.CallbackPoster {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: netguard.Control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialled instead of the client's host and defeat the address check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &callbackPoster{
+		secret: secret,
+		client: &http.Client{Timeout: 5 * time.Second, Transport: transport},
+	}
+}
+
+// sign returns the hex HMAC-SHA256 over secret of timestamp + "." + body, as sent in X-Signature
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (p *callbackPoster) Post(ctx context.Context, url string, body []byte) error {
+	if p.secret == "" {
+		return errors.New("no signing secret configured, refusing to send an unsigned callback")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// The timestamp is signed with the body so receivers can turn away replayed callbacks
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	req.Header.Set("X-Signature", sign(p.secret, timestamp, body))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -10,17 +10,24 @@ import (
 
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// webhookKeyHeader carries the configured webhook key to the provider
+const webhookKeyHeader = "x-ins-auth-key"
+
 type webhookSender struct {
-	webhookURL string
-	client     *http.Client
+	config ports.ConfigProvider
+	client *http.Client
 }
 
-func NewWebhookSender(webhookURL string) ports.MessageSender {
+// NewWebhookSender posts each message to the webhook of its tenant, or the app-wide one
+// when the tenant doesn't override it
+func NewWebhookSender(config ports.ConfigProvider) ports.MessageSender {
 	return &webhookSender{
-		webhookURL: webhookURL,
-		client:     &http.Client{Timeout: 5 * time.Second},
+		config: config,
+		// The transport starts a client span and injects its W3C traceparent into the request
+		client: &http.Client{Timeout: 5 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
 	}
 }
 
@@ -30,17 +37,30 @@ func (w *webhookSender) Send(ctx context.Context, message domain.Message) (strin
 		"content": message.Content,
 	}
 
+	// Concatenation metadata lets the provider build the UDH so the handset reassembles the parts
+	if message.ConcatRef != 0 && message.PartTotal > 1 {
+		payload["concat"] = map[string]int{
+			"ref":   message.ConcatRef,
+			"total": message.PartTotal,
+			"seq":   message.PartNumber,
+		}
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", w.webhookURL, bytes.NewBuffer(jsonData))
+	settings := w.config.Current().TenantSettings(message.TenantID)
+	req, err := http.NewRequestWithContext(ctx, "POST", settings.WebhookURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if settings.WebhookKey != "" {
+		req.Header.Set(webhookKeyHeader, settings.WebhookKey)
+	}
 
 	resp, err := w.client.Do(req)
 	if err != nil {
@@ -49,7 +69,7 @@ func (w *webhookSender) Send(ctx context.Context, message domain.Message) (strin
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 202 {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return "", &domain.ProviderError{StatusCode: resp.StatusCode}
 	}
 
 	var response map[string]interface{}
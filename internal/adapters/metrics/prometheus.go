@@ -0,0 +1,227 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "messenger"
+	// queueDepthTimeout bounds the count query run on every scrape
+	queueDepthTimeout = 2 * time.Second
+)
+
+// Prometheus records service metrics in its own registry and serves them for scraping
+type Prometheus struct {
+	registry *prometheus.Registry
+	provider string
+
+	sent           *prometheus.CounterVec
+	failed         *prometheus.CounterVec
+	retried        *prometheus.CounterVec
+	webhookLatency *prometheus.HistogramVec
+	tickDuration   prometheus.Histogram
+	senderRunning  prometheus.Gauge
+}
+
+// NewPrometheus creates the metrics registry; provider labels every dispatch metric
+func NewPrometheus(provider string) *Prometheus {
+	p := &Prometheus{
+		registry: prometheus.NewRegistry(),
+		provider: provider,
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_sent_total",
+			Help:      "Messages accepted by the provider.",
+		}, []string{"provider"}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_failed_total",
+			Help:      "Messages marked failed, by the class of the final error.",
+		}, []string{"provider", "error_class"}),
+		retried: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_retried_total",
+			Help:      "Failed send attempts that will be retried, by error class.",
+		}, []string{"provider", "error_class"}),
+		webhookLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "webhook_request_duration_seconds",
+			Help:      "Latency of provider webhook calls.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2, 3, 5},
+		}, []string{"provider", "outcome"}),
+		tickDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "sender_tick_duration_seconds",
+			Help:      "Time taken by one auto-sender pass over pending messages.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		senderRunning: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sender_running",
+			Help:      "1 while the auto-sender is running.",
+		}),
+	}
+
+	p.registry.MustRegister(p.sent, p.failed, p.retried, p.webhookLatency, p.tickDuration, p.senderRunning,
+		collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return p
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{Registry: p.registry})
+}
+
+// WatchQueue exposes the number of pending messages, counted when scraped with the retry
+// ceiling configured at that moment
+func (p *Prometheus) WatchQueue(repo ports.MessageRepository, config ports.ConfigProvider) {
+	p.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pending_messages",
+		Help:      "Messages waiting to be sent, including deferred ones.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), queueDepthTimeout)
+		defer cancel()
+
+		cfg := config.Current()
+		count, err := repo.CountPendingMessages(ctx, cfg.MaxRetriesCeiling())
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to count pending messages for metrics", "error", err)
+			return -1
+		}
+		return float64(count)
+	}))
+}
+
+// WatchBreaker exposes the circuit breaker state as 0 closed, 1 half-open, 2 open. With a
+// breaker per tenant the gauge shows the worst of them and the trips add up.
+func (p *Prometheus) WatchBreaker(breaker ports.CircuitBreaker) {
+	p.registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "circuit_breaker_state",
+			Help:      "Worst webhook circuit breaker state: 0 closed, 1 half-open, 2 open.",
+		}, func() float64 {
+			status := breaker.Status()
+			state := breakerState(status.State)
+			for _, tenant := range status.Tenants {
+				state = max(state, breakerState(tenant.State))
+			}
+			return state
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "circuit_breaker_trips_total",
+			Help:      "Times a webhook circuit breaker opened.",
+		}, func() float64 {
+			status := breaker.Status()
+			trips := status.Trips
+			for _, tenant := range status.Tenants {
+				trips += tenant.Trips
+			}
+			return float64(trips)
+		}),
+	)
+}
+
+func breakerState(state domain.BreakerState) float64 {
+	switch state {
+	case domain.BreakerOpen:
+		return 2
+	case domain.BreakerHalfOpen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// InstrumentSender times every call to next, which should be the provider webhook itself
+// so breaker rejections and rate limit waits don't count as latency
+func (p *Prometheus) InstrumentSender(next ports.MessageSender) ports.MessageSender {
+	return &instrumentedSender{next: next, metrics: p}
+}
+
+func (p *Prometheus) MessageSent() {
+	p.sent.WithLabelValues(p.provider).Inc()
+}
+
+func (p *Prometheus) MessageFailed(err error) {
+	p.failed.WithLabelValues(p.provider, ErrorClass(err)).Inc()
+}
+
+func (p *Prometheus) MessageRetried(err error) {
+	p.retried.WithLabelValues(p.provider, ErrorClass(err)).Inc()
+}
+
+func (p *Prometheus) ObserveTick(duration time.Duration) {
+	p.tickDuration.Observe(duration.Seconds())
+}
+
+func (p *Prometheus) SetSenderRunning(running bool) {
+	if running {
+		p.senderRunning.Set(1)
+		return
+	}
+	p.senderRunning.Set(0)
+}
+
+type instrumentedSender struct {
+	next    ports.MessageSender
+	metrics *Prometheus
+}
+
+func (s *instrumentedSender) Send(ctx context.Context, message domain.Message) (string, error) {
+	start := time.Now()
+	messageID, err := s.next.Send(ctx, message)
+
+	outcome := "success"
+	if err != nil {
+		outcome = ErrorClass(err)
+	}
+	s.metrics.webhookLatency.WithLabelValues(s.metrics.provider, outcome).Observe(time.Since(start).Seconds())
+	return messageID, err
+}
+
+// ErrorClass buckets a send error into a low-cardinality label value
+func ErrorClass(err error) string {
+	var provider *domain.ProviderError
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return "none"
+	case errors.Is(err, domain.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, domain.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, domain.ErrInvalidMessage):
+		return "invalid_message"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &provider):
+		if provider.StatusCode >= 500 {
+			return "provider_5xx"
+		}
+		return "provider_4xx"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+// APIKeyContextKey holds the authenticated *domain.APIKey in the gin context
+const APIKeyContextKey = "api_key"
+
+type AuthHandler struct {
+	authService ports.AuthService
+}
+
+func NewAuthHandler(authService ports.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+	// Role is one of read, send, operator or admin; each includes the ones before it
+	Role domain.Role `json:"role" binding:"required"`
+	// TenantID confines the key to a configured tenant, which allows the read and send roles
+	TenantID  string     `json:"tenant_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	Key domain.APIKey `json:"key"`
+	// Secret is the API key itself; only its hash is stored, so it can't be shown again
+	Secret string `json:"secret"`
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Creates a key with the given role, optionally confined to one tenant. The response is the only time the secret is returned. Send it in the X-API-Key header or as an Authorization bearer token.
+// @Tags Admin
+// @Accept json
+// @Param key body CreateAPIKeyRequest true "Key name, role and optional tenant and expiry"
+// @Success 201 {object} CreateAPIKeyResponse
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /admin/keys [post]
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, secret, err := h.authService.CreateKey(c.Request.Context(), req.Name, req.Role, req.TenantID,
+		req.ExpiresAt)
+	if errors.Is(err, domain.ErrInvalidAPIKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{Key: *key, Secret: secret})
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Returns every key, including revoked and expired ones, without their secrets
+// @Tags Admin
+// @Success 200 {array} domain.APIKey
+// @Security ApiKeyAuth
+// @Router /admin/keys [get]
+func (h *AuthHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.authService.ListKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revokes a key immediately. The key stays listed so its audit entries can still be traced to it.
+// @Tags Admin
+// @Param id path int true "API key ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /admin/keys/{id} [delete]
+func (h *AuthHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key id"})
+		return
+	}
+
+	err = h.authService.RevokeKey(c.Request.Context(), uint(id))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+// ListAudit godoc
+// @Summary Audit log
+// @Description Returns the newest state-changing API calls with the key that made them, including calls refused for lack of a role
+// @Tags Admin
+// @Param key_id query int false "Only calls made with this API key"
+// @Param limit query int false "Number of entries, default 100, at most 1000"
+// @Success 200 {array} domain.AuditEntry
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /admin/audit [get]
+func (h *AuthHandler) ListAudit(c *gin.Context) {
+	var filter domain.AuditFilter
+	if raw := c.Query("key_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 0)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid key_id"})
+			return
+		}
+		filter.APIKeyID = uint(id)
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.authService.ListAudit(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// APIKeyOf returns the key that authenticated the request, nil when authentication is off
+func APIKeyOf(c *gin.Context) *domain.APIKey {
+	value, ok := c.Get(APIKeyContextKey)
+	if !ok {
+		return nil
+	}
+	key, _ := value.(*domain.APIKey)
+	return key
+}
+
+// callerTenant is the tenant the request is confined to, empty for deployment-wide keys
+// and when authentication is off
+func callerTenant(c *gin.Context) string {
+	if key := APIKeyOf(c); key != nil {
+		return key.TenantID
+	}
+	return ""
+}
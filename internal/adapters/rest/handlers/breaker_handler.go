@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type BreakerHandler struct {
+	breaker ports.CircuitBreaker
+}
+
+func NewBreakerHandler(breaker ports.CircuitBreaker) *BreakerHandler {
+	return &BreakerHandler{
+		breaker: breaker,
+	}
+}
+
+// GetBreakerStatus godoc
+// @Summary Circuit breaker status
+// @Description Returns the state and counters of the circuit breaker guarding the webhook sender, with each tenant's own breaker under tenants. Not available to tenant keys.
+// @Tags AutoSender
+// @Success 200 {object} domain.BreakerStatus
+// @Failure 403 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /sender/breaker [get]
+func (h *BreakerHandler) GetBreakerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.breaker.Status())
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+const signatureHeader = "X-Signature"
+
+// maxCallbackBody caps what an unauthenticated caller can make us read before the signature check
+const maxCallbackBody = 64 << 10
+
+type CallbackHandler struct {
+	messageService     ports.MessageService
+	suppressionService ports.SuppressionService
+	secret             string
+}
+
+func NewCallbackHandler(messageService ports.MessageService, suppressionService ports.SuppressionService,
+	secret string) *CallbackHandler {
+	return &CallbackHandler{
+		messageService:     messageService,
+		suppressionService: suppressionService,
+		secret:             secret,
+	}
+}
+
+type DeliveryReceiptRequest struct {
+	MessageID string `json:"messageId" binding:"required"`
+	Status    string `json:"status" binding:"required"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// DeliveryReceipt godoc
+// @Summary Delivery receipt callback
+// @Description Accepts a provider delivery receipt keyed by provider messageId. The raw body must be signed with HMAC-SHA256 using the shared callback secret and the hex digest sent in the X-Signature header. Duplicate receipts are acknowledged without changes.
+// @Tags Callbacks
+// @Accept json
+// @Param X-Signature header string true "Hex HMAC-SHA256 of the request body"
+// @Param receipt body DeliveryReceiptRequest true "Delivery receipt"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} FailResponse
+// @Failure 401 {object} FailResponse
+// @Failure 413 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Failure 409 {object} FailResponse
+// @Router /callbacks/delivery [post]
+func (h *CallbackHandler) DeliveryReceipt(c *gin.Context) {
+	if h.secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Delivery callbacks are not configured"})
+		return
+	}
+
+	body, ok := h.signedBody(c)
+	if !ok {
+		return
+	}
+
+	var req DeliveryReceiptRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.MessageID == "" || req.Status == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "messageId and status are required"})
+		return
+	}
+
+	status := domain.Status(strings.ToLower(req.Status))
+	if status != domain.StatusDelivered && status != domain.StatusUndelivered {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be delivered or undelivered"})
+		return
+	}
+
+	err := h.messageService.ProcessDeliveryReceipt(c.Request.Context(), domain.DeliveryReceipt{
+		ProviderMessageID: req.MessageID,
+		Status:            status,
+		ErrorCode:         req.ErrorCode,
+	})
+	switch {
+	case errors.Is(err, domain.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrStatusConflict), errors.Is(err, domain.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process delivery receipt"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Delivery receipt processed"})
+}
+
+type InboundMessageRequest struct {
+	From string `json:"from" binding:"required"`
+	Text string `json:"text"`
+}
+
+type InboundMessageResponse struct {
+	Action domain.InboundAction `json:"action"`
+}
+
+// InboundMessage godoc
+// @Summary Inbound message callback
+// @Description Accepts a reply relayed by the provider, signed like delivery receipts. Replies containing an opt-out keyword such as STOP add the sender to the suppression list; START lifts an opt-out the sender made by keyword.
+// @Tags Callbacks
+// @Accept json
+// @Param X-Signature header string true "Hex HMAC-SHA256 of the request body"
+// @Param message body InboundMessageRequest true "Inbound message"
+// @Success 200 {object} InboundMessageResponse
+// @Failure 400 {object} FailResponse
+// @Failure 401 {object} FailResponse
+// @Failure 413 {object} FailResponse
+// @Router /callbacks/inbound [post]
+func (h *CallbackHandler) InboundMessage(c *gin.Context) {
+	if h.secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Inbound callbacks are not configured"})
+		return
+	}
+
+	body, ok := h.signedBody(c)
+	if !ok {
+		return
+	}
+
+	var req InboundMessageRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.From == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from is required"})
+		return
+	}
+
+	action, err := h.suppressionService.HandleInbound(c.Request.Context(),
+		domain.InboundMessage{From: req.From, Text: req.Text})
+	if errors.Is(err, domain.ErrInvalidRecipient) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process inbound message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, InboundMessageResponse{Action: action})
+}
+
+// signedBody reads the request body and rejects it unless it carries a valid signature
+func (h *CallbackHandler) signedBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxCallbackBody))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body is too large"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return nil, false
+	}
+
+	if !validSignature(h.secret, body, c.GetHeader(signatureHeader)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return nil, false
+	}
+	return body, true
+}
+
+// validSignature compares the hex HMAC-SHA256 of body against the received signature in constant time
+func validSignature(secret string, body []byte, signature string) bool {
+	received, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || len(received) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), received)
+}
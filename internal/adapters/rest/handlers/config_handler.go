@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type ConfigHandler struct {
+	configService ports.ConfigService
+}
+
+func NewConfigHandler(configService ports.ConfigService) *ConfigHandler {
+	return &ConfigHandler{
+		configService: configService,
+	}
+}
+
+// ReloadConfig godoc
+// @Summary Reload configuration
+// @Description Re-reads config.yaml and the environment. If the result is valid, the send interval, batch size, retry limit and log level take effect without a restart; other changed sections are listed as needing one. An invalid configuration is rejected and the running one is kept. Sending SIGHUP to the process does the same.
+// @Tags Admin
+// @Success 200 {object} domain.ConfigReload
+// @Failure 400 {object} FailResponse
+// @Failure 500 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /admin/config/reload [post]
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	result, err := h.configService.Reload(c.Request.Context())
+	if errors.Is(err, domain.ErrInvalidConfig) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
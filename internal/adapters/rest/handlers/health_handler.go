@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type HealthHandler struct {
+	healthService ports.HealthService
+}
+
+func NewHealthHandler(healthService ports.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Healthz godoc
+// @Summary Liveness probe
+// @Description Reports whether the process is working. Fails when the auto-sender is running but hasn't completed a tick for three intervals; database and Redis outages don't fail it, so they don't get the process restarted.
+// @Tags Utility
+// @Success 200 {object} domain.HealthReport
+// @Failure 503 {object} domain.HealthReport
+// @Router /healthz [get]
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	respondHealth(c, h.healthService.Liveness(c.Request.Context()))
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Reports whether the instance can serve traffic: Postgres and Redis respond to a ping and the auto-sender isn't stalled. Returns 503 with per-dependency status when any check fails.
+// @Tags Utility
+// @Success 200 {object} domain.HealthReport
+// @Failure 503 {object} domain.HealthReport
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	respondHealth(c, h.healthService.Readiness(c.Request.Context()))
+}
+
+func respondHealth(c *gin.Context, report domain.HealthReport) {
+	status := http.StatusOK
+	if report.Status != domain.HealthUp {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
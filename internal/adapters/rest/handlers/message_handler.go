@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"context"
-	"github.com/hasElvin/messenger-svc/config"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/hasElvin/messenger-svc/internal/core/ports"
 )
 
@@ -19,18 +22,32 @@ func NewMessageHandler(messageService ports.MessageService) *MessageHandler {
 	}
 }
 
+// StartSenderRequest overrides the configured send interval and batch size until the sender is
+// next started; fields left out follow the configuration, including reloads
+type StartSenderRequest struct {
+	IntervalSeconds int `json:"interval_seconds,omitempty" binding:"omitempty,min=1,max=86400"`
+	BatchSize       int `json:"batch_size,omitempty" binding:"omitempty,min=1,max=1000"`
+}
+
 // StartAutoSender godoc
 // @Summary Start auto-sender
-// @Description Starts the automatic message sending process
+// @Description Starts the automatic message sending process. The body is optional; fields left out follow the configured interval and batch size, including later reloads.
 // @Tags AutoSender
+// @Accept json
+// @Param options body StartSenderRequest false "Interval and batch size overrides"
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
 // @Router /start [post]
 func (h *MessageHandler) StartAutoSender(c *gin.Context) {
-	cfg := config.LoadConfig()
-	intervalSeconds := cfg.App.SendIntervalSecs
+	var req StartSenderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	err := h.messageService.StartAutoSender(context.Background(), intervalSeconds)
+	err := h.messageService.StartAutoSender(context.WithoutCancel(c.Request.Context()),
+		req.IntervalSeconds, req.BatchSize)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -45,6 +62,7 @@ func (h *MessageHandler) StartAutoSender(c *gin.Context) {
 // @Tags AutoSender
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
 // @Router /stop [post]
 func (h *MessageHandler) StopAutoSender(c *gin.Context) {
 	err := h.messageService.StopAutoSender(c.Request.Context())
@@ -56,14 +74,33 @@ func (h *MessageHandler) StopAutoSender(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Auto sender stopped"})
 }
 
+// GetSenderStatus godoc
+// @Summary Auto-sender status
+// @Description Returns whether the auto-sender is running, its interval and batch size, when the last tick finished and how many messages it picked up, when the next tick is due, and outcome counters since it was started. Not available to tenant keys.
+// @Tags AutoSender
+// @Success 200 {object} domain.SenderStatus
+// @Failure 403 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /sender/status [get]
+func (h *MessageHandler) GetSenderStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.messageService.SenderStatus())
+}
+
 // GetSentMessages godoc
 // @Summary List all sent messages
-// @Description Returns a list of messages that were sent by the auto-sender
+// @Description Returns a list of messages that were sent by the auto-sender, including those a delivery receipt has since marked delivered or undelivered. Tenant keys only ever see their own tenant's messages; other keys see every tenant's unless tenant_id is given.
 // @Tags Messages
+// @Param tenant_id query string false "Only this tenant's messages"
 // @Success 200 {array} domain.Message
+// @Security ApiKeyAuth
 // @Router /sent [get]
 func (h *MessageHandler) GetSentMessages(c *gin.Context) {
-	messages, err := h.messageService.GetSentMessages(c.Request.Context())
+	tenantID := callerTenant(c)
+	if tenantID == "" {
+		tenantID = c.Query("tenant_id")
+	}
+
+	messages, err := h.messageService.GetSentMessages(c.Request.Context(), tenantID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve sent messages"})
 		return
@@ -71,3 +108,136 @@ func (h *MessageHandler) GetSentMessages(c *gin.Context) {
 
 	c.JSON(http.StatusOK, messages)
 }
+
+type EnqueueMessageRequest struct {
+	To      string `json:"to" binding:"required"`
+	Content string `json:"content,omitempty"`
+	// TemplateID renders content from a template instead; TemplateVersion pins a version
+	TemplateID      uint              `json:"template_id,omitempty"`
+	TemplateVersion int               `json:"template_version,omitempty"`
+	Variables       map[string]string `json:"variables,omitempty"`
+	// Locale picks the template variant; defaults to the recipient's stored preference
+	Locale        string `json:"locale,omitempty"`
+	CallbackURL   string `json:"callback_url,omitempty"`
+	Transliterate bool   `json:"transliterate,omitempty"`
+	// Category selects quiet hours, e.g. marketing; Timezone overrides the recipient's IANA zone
+	Category string `json:"category,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	// TenantID sends for a configured tenant; tenant keys always send for their own
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// EnqueueMessage godoc
+// @Summary Enqueue a message
+// @Description Queues a message for the auto-sender. The recipient is normalized to E.164, reading numbers without a country code as numbers of the configured default region, and rejected if it isn't a valid mobile number. If callback_url is set, signed status events are POSTed to it when the message is sent, fails or gets a delivery receipt. Either content or template_id is required; templates are rendered with variables in the requested locale (or the recipient's preferred one), following the configured fallback chain, and the result is checked against the same length limits. Messages in a category with quiet hours are held until the window ends in the recipient's timezone (explicit, stored preference or derived from the country code). Depending on the dedupe mode, the same content to the same recipient within the dedupe window is rejected with 409 or stored and later marked duplicate instead of sent. With transliterate, accented characters are replaced by GSM-7 equivalents before the message is stored. Messages of a tenant are sent to its webhook and checked against its character and retry limits where it overrides them.
+// @Tags Messages
+// @Accept json
+// @Param message body EnqueueMessageRequest true "Message to send"
+// @Success 201 {object} domain.Message
+// @Failure 400 {object} FailResponse
+// @Failure 403 {object} FailResponse
+// @Failure 409 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /messages [post]
+func (h *MessageHandler) EnqueueMessage(c *gin.Context) {
+	var req EnqueueMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := req.TenantID
+	if own := callerTenant(c); own != "" {
+		if tenantID != "" && tenantID != own {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This API key can only send for tenant " + own})
+			return
+		}
+		tenantID = own
+	}
+
+	message := domain.Message{
+		TenantID:        tenantID,
+		To:              req.To,
+		Content:         req.Content,
+		TemplateID:      req.TemplateID,
+		TemplateVersion: req.TemplateVersion,
+		Variables:       req.Variables,
+		Locale:          req.Locale,
+		Category:        req.Category,
+		Timezone:        req.Timezone,
+		CallbackURL:     req.CallbackURL,
+		Transliterate:   req.Transliterate,
+	}
+
+	err := h.messageService.EnqueueMessage(c.Request.Context(), &message)
+	var duplicate *domain.DuplicateError
+	if errors.As(err, &duplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "duplicate_of": duplicate.OriginalID})
+		return
+	}
+	if errors.Is(err, domain.ErrInvalidMessage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to enqueue message"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// CancelMessage godoc
+// @Summary Cancel a pending message
+// @Description Withdraws a message that hasn't been sent yet, along with its parts still pending. Tenant keys only find their own tenant's messages. A message that is no longer pending gets 409. The sender may be handing the message to the provider at that very moment, in which case it can still arrive.
+// @Tags Messages
+// @Param id path int true "Message ID"
+// @Success 200 {object} domain.Message
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Failure 409 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /messages/{id}/cancel [post]
+func (h *MessageHandler) CancelMessage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message id"})
+		return
+	}
+
+	message, err := h.messageService.CancelMessage(c.Request.Context(), uint(id), callerTenant(c))
+	switch {
+	case errors.Is(err, domain.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrStatusConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel message"})
+	default:
+		c.JSON(http.StatusOK, message)
+	}
+}
+
+type PreviewRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// PreviewTransliteration godoc
+// @Summary Preview transliteration
+// @Description Shows content as written and transliterated to GSM-7, with the encoding and segment count of each
+// @Tags Messages
+// @Accept json
+// @Param preview body PreviewRequest true "Content to preview"
+// @Success 200 {object} domain.TransliterationPreview
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /messages/preview [post]
+func (h *MessageHandler) PreviewTransliteration(c *gin.Context) {
+	var req PreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.messageService.PreviewTransliteration(req.Content))
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type RecipientHandler struct {
+	recipientService ports.RecipientService
+}
+
+func NewRecipientHandler(recipientService ports.RecipientService) *RecipientHandler {
+	return &RecipientHandler{
+		recipientService: recipientService,
+	}
+}
+
+type RecipientPreferencesRequest struct {
+	Locale   string `json:"locale"`
+	Timezone string `json:"timezone"`
+}
+
+// GetRecipient godoc
+// @Summary Get recipient preferences
+// @Description Returns the stored preferences for a phone number in any accepted format
+// @Tags Recipients
+// @Param phone path string true "Phone number"
+// @Success 200 {object} domain.Recipient
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /recipients/{phone} [get]
+func (h *RecipientHandler) GetRecipient(c *gin.Context) {
+	recipient, err := h.recipientService.GetRecipient(c.Request.Context(), c.Param("phone"))
+	if err != nil {
+		respondRecipientError(c, err, "Failed to retrieve recipient")
+		return
+	}
+
+	c.JSON(http.StatusOK, recipient)
+}
+
+// SavePreferences godoc
+// @Summary Save recipient preferences
+// @Description Stores preferences for a phone number. The locale is used to pick a template variant and the IANA timezone to apply quiet hours when a message doesn't specify them.
+// @Tags Recipients
+// @Accept json
+// @Param phone path string true "Phone number"
+// @Param preferences body RecipientPreferencesRequest true "Preferences"
+// @Success 200 {object} domain.Recipient
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /recipients/{phone} [put]
+func (h *RecipientHandler) SavePreferences(c *gin.Context) {
+	var req RecipientPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	recipient := domain.Recipient{Phone: c.Param("phone"), Locale: req.Locale, Timezone: req.Timezone}
+	if err := h.recipientService.SavePreferences(c.Request.Context(), &recipient); err != nil {
+		respondRecipientError(c, err, "Failed to save recipient preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, recipient)
+}
+
+func respondRecipientError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrRecipientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidRecipient):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
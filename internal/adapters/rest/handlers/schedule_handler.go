@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+const schedulePreviewRuns = 10
+
+type ScheduleHandler struct {
+	scheduleService ports.ScheduleService
+}
+
+func NewScheduleHandler(scheduleService ports.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+type ScheduleRequest struct {
+	Name string `json:"name" binding:"required"`
+	// Cron is a standard five-field expression (minute hour day month weekday) or a descriptor such as @weekly
+	Cron string `json:"cron" binding:"required"`
+	// Timezone is the IANA zone the expression is evaluated in; defaults to UTC
+	Timezone   string            `json:"timezone,omitempty"`
+	TemplateID uint              `json:"template_id" binding:"required"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	Locale     string            `json:"locale,omitempty"`
+	Category   string            `json:"category,omitempty"`
+	Recipients []string          `json:"recipients" binding:"required"`
+	StartAt    *time.Time        `json:"start_at,omitempty"`
+	EndAt      *time.Time        `json:"end_at,omitempty"`
+}
+
+type SchedulePreviewResponse struct {
+	Runs []time.Time `json:"runs"`
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring schedule
+// @Description Creates a schedule that enqueues the template for every recipient at each occurrence of the cron expression in its timezone, between the optional start and end dates. Recipients are normalized like message recipients and scheduled messages go through the same checks.
+// @Tags Schedules
+// @Accept json
+// @Param schedule body ScheduleRequest true "Schedule"
+// @Success 201 {object} domain.Schedule
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /schedules [post]
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := req.toDomain()
+	if err := h.scheduleService.CreateSchedule(c.Request.Context(), &schedule); err != nil {
+		respondScheduleError(c, err, "Failed to create schedule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListSchedules godoc
+// @Summary List recurring schedules
+// @Tags Schedules
+// @Success 200 {array} domain.Schedule
+// @Security ApiKeyAuth
+// @Router /schedules [get]
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.scheduleService.ListSchedules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schedules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetSchedule godoc
+// @Summary Get a recurring schedule
+// @Tags Schedules
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} domain.Schedule
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /schedules/{id} [get]
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.scheduleService.GetSchedule(c.Request.Context(), id)
+	if err != nil {
+		respondScheduleError(c, err, "Failed to retrieve schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule godoc
+// @Summary Update a recurring schedule
+// @Description Replaces a schedule; its next run is recomputed from now
+// @Tags Schedules
+// @Accept json
+// @Param id path int true "Schedule ID"
+// @Param schedule body ScheduleRequest true "Schedule"
+// @Success 200 {object} domain.Schedule
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /schedules/{id} [put]
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule := req.toDomain()
+	schedule.ID = id
+	if err := h.scheduleService.UpdateSchedule(c.Request.Context(), &schedule); err != nil {
+		respondScheduleError(c, err, "Failed to update schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a recurring schedule
+// @Description Deletes a schedule; messages it already enqueued are still sent
+// @Tags Schedules
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /schedules/{id} [delete]
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.scheduleService.DeleteSchedule(c.Request.Context(), id); err != nil {
+		respondScheduleError(c, err, "Failed to delete schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule deleted"})
+}
+
+// PreviewSchedule godoc
+// @Summary Preview upcoming runs
+// @Description Returns the next 10 occurrences of a schedule in its timezone, fewer if it ends sooner
+// @Tags Schedules
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} SchedulePreviewResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /schedules/{id}/preview [get]
+func (h *ScheduleHandler) PreviewSchedule(c *gin.Context) {
+	id, ok := scheduleID(c)
+	if !ok {
+		return
+	}
+
+	runs, err := h.scheduleService.PreviewRuns(c.Request.Context(), id, schedulePreviewRuns)
+	if err != nil {
+		respondScheduleError(c, err, "Failed to preview schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, SchedulePreviewResponse{Runs: runs})
+}
+
+func (r ScheduleRequest) toDomain() domain.Schedule {
+	return domain.Schedule{
+		Name:       r.Name,
+		Cron:       r.Cron,
+		Timezone:   r.Timezone,
+		TemplateID: r.TemplateID,
+		Variables:  r.Variables,
+		Locale:     r.Locale,
+		Category:   r.Category,
+		Recipients: r.Recipients,
+		StartAt:    r.StartAt,
+		EndAt:      r.EndAt,
+	}
+}
+
+func scheduleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondScheduleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrScheduleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type SuppressionHandler struct {
+	suppressionService ports.SuppressionService
+}
+
+func NewSuppressionHandler(suppressionService ports.SuppressionService) *SuppressionHandler {
+	return &SuppressionHandler{
+		suppressionService: suppressionService,
+	}
+}
+
+type SuppressionRequest struct {
+	Phone  string `json:"phone" binding:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ListSuppressions godoc
+// @Summary List suppressed numbers
+// @Description Returns every phone number on the opt-out list, newest first
+// @Tags Suppressions
+// @Success 200 {array} domain.Suppression
+// @Security ApiKeyAuth
+// @Router /suppressions [get]
+func (h *SuppressionHandler) ListSuppressions(c *gin.Context) {
+	suppressions, err := h.suppressionService.ListSuppressions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve suppressions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, suppressions)
+}
+
+// Suppress godoc
+// @Summary Suppress a number
+// @Description Adds a phone number to the opt-out list. Pending messages to it are marked suppressed instead of being sent. Adding a number twice keeps the original entry.
+// @Tags Suppressions
+// @Accept json
+// @Param suppression body SuppressionRequest true "Number to suppress"
+// @Success 201 {object} domain.Suppression
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /suppressions [post]
+func (h *SuppressionHandler) Suppress(c *gin.Context) {
+	var req SuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	suppression := domain.Suppression{Phone: req.Phone, Source: domain.SuppressionManual, Reason: req.Reason}
+	err := h.suppressionService.Suppress(c.Request.Context(), &suppression)
+	if errors.Is(err, domain.ErrInvalidRecipient) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to suppress number"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, suppression)
+}
+
+// Unsuppress godoc
+// @Summary Remove a suppressed number
+// @Description Removes a phone number from the opt-out list
+// @Tags Suppressions
+// @Param phone path string true "Phone number"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /suppressions/{phone} [delete]
+func (h *SuppressionHandler) Unsuppress(c *gin.Context) {
+	err := h.suppressionService.Unsuppress(c.Request.Context(), c.Param("phone"))
+	switch {
+	case errors.Is(err, domain.ErrInvalidRecipient):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrSuppressionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove suppression"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Suppression removed"})
+}
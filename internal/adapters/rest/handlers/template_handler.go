@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type TemplateHandler struct {
+	templateService ports.TemplateService
+}
+
+func NewTemplateHandler(templateService ports.TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+	}
+}
+
+type TemplateRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description,omitempty"`
+	// Locale is the language of Body; defaults to the configured default locale
+	Locale   string            `json:"locale,omitempty"`
+	Body     string            `json:"body" binding:"required"`
+	Variants map[string]string `json:"variants,omitempty"`
+}
+
+// CreateTemplate godoc
+// @Summary Create a template
+// @Description Creates a message template. Placeholders are written as {{name}} and become the template's required variables. Variants hold translations keyed by locale and may only use the body's variables.
+// @Tags Templates
+// @Accept json
+// @Param template body TemplateRequest true "Template"
+// @Success 201 {object} domain.Template
+// @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template := domain.Template{Name: req.Name, Description: req.Description,
+		Locale: req.Locale, Body: req.Body, Variants: req.Variants}
+	if err := h.templateService.CreateTemplate(c.Request.Context(), &template); err != nil {
+		respondTemplateError(c, err, "Failed to create template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplates godoc
+// @Summary List templates
+// @Description Returns every template at its latest version
+// @Tags Templates
+// @Success 200 {array} domain.Template
+// @Security ApiKeyAuth
+// @Router /templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate godoc
+// @Summary Get a template
+// @Description Returns the latest version of a template, or the given version
+// @Tags Templates
+// @Param id path int true "Template ID"
+// @Param version query int false "Template version"
+// @Success 200 {object} domain.Template
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	version, err := strconv.Atoi(c.DefaultQuery("version", "0"))
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(c.Request.Context(), id, version)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to retrieve template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update a template
+// @Description Updates a template. Changed bodies or variants are stored as a new version; messages already queued keep the version they were rendered from.
+// @Tags Templates
+// @Accept json
+// @Param id path int true "Template ID"
+// @Param template body TemplateRequest true "Template"
+// @Success 200 {object} domain.Template
+// @Failure 400 {object} FailResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template := domain.Template{ID: id, Name: req.Name, Description: req.Description,
+		Locale: req.Locale, Body: req.Body, Variants: req.Variants}
+	if err := h.templateService.UpdateTemplate(c.Request.Context(), &template); err != nil {
+		respondTemplateError(c, err, "Failed to update template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a template
+// @Description Deletes a template. Its versions are kept so sent messages remain traceable.
+// @Tags Templates
+// @Param id path int true "Template ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), id); err != nil {
+		respondTemplateError(c, err, "Failed to delete template")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted"})
+}
+
+// ListTemplateVersions godoc
+// @Summary List template versions
+// @Description Returns every version of a template in every locale, newest first
+// @Tags Templates
+// @Param id path int true "Template ID"
+// @Success 200 {array} domain.TemplateVersion
+// @Failure 404 {object} FailResponse
+// @Security ApiKeyAuth
+// @Router /templates/{id}/versions [get]
+func (h *TemplateHandler) ListTemplateVersions(c *gin.Context) {
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.templateService.ListTemplateVersions(c.Request.Context(), id)
+	if err != nil {
+		respondTemplateError(c, err, "Failed to retrieve template versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+func templateID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template id"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondTemplateError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, domain.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
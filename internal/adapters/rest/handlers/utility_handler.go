@@ -32,6 +32,7 @@ func (h *UtilityHandler) Ping(c *gin.Context) {
 // @Tags Utility
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
 // @Router /seed [post]
 func (h *UtilityHandler) SeedSampleMessages(c *gin.Context) {
 	err := h.utilityService.SeedSampleMessages()
@@ -48,6 +49,7 @@ func (h *UtilityHandler) SeedSampleMessages(c *gin.Context) {
 // @Tags Utility
 // @Success 200 {object} SuccessResponse
 // @Failure 400 {object} FailResponse
+// @Security ApiKeyAuth
 // @Router /clear [delete]
 func (h *UtilityHandler) ClearDatabase(c *gin.Context) {
 	err := h.utilityService.ClearDatabase()
@@ -1,32 +1,85 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	_ "github.com/hasElvin/messenger-svc/docs"
 	"github.com/hasElvin/messenger-svc/internal/adapters/rest/handlers"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/hasElvin/messenger-svc/internal/core/ports"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
+// serviceName names the server spans
+const serviceName = "messenger-svc"
+
+// readHeaderTimeout drops clients that open a connection and never finish the request headers
+const readHeaderTimeout = 10 * time.Second
+
 type Server struct {
-	messageHandler *handlers.MessageHandler
-	utilityHandler *handlers.UtilityHandler
-	router         *gin.Engine
+	messageHandler     *handlers.MessageHandler
+	utilityHandler     *handlers.UtilityHandler
+	breakerHandler     *handlers.BreakerHandler
+	callbackHandler    *handlers.CallbackHandler
+	templateHandler    *handlers.TemplateHandler
+	recipientHandler   *handlers.RecipientHandler
+	suppressionHandler *handlers.SuppressionHandler
+	scheduleHandler    *handlers.ScheduleHandler
+	healthHandler      *handlers.HealthHandler
+	configHandler      *handlers.ConfigHandler
+	authHandler        *handlers.AuthHandler
+	authService        ports.AuthService
+	authEnabled        bool
+	metricsHandler     http.Handler
+	router             *gin.Engine
+	httpServer         *http.Server
 }
 
-func NewServer(messageService ports.MessageService, utilityService ports.UtilityService) *Server {
+func NewServer(messageService ports.MessageService, utilityService ports.UtilityService,
+	templateService ports.TemplateService, recipientService ports.RecipientService,
+	suppressionService ports.SuppressionService, scheduleService ports.ScheduleService,
+	healthService ports.HealthService, configService ports.ConfigService, authService ports.AuthService,
+	breaker ports.CircuitBreaker, metricsHandler http.Handler, callbackSecret string, authEnabled bool) *Server {
 	messageHandler := handlers.NewMessageHandler(messageService)
 	utilityHandler := handlers.NewUtilityHandler(utilityService)
+	breakerHandler := handlers.NewBreakerHandler(breaker)
+	callbackHandler := handlers.NewCallbackHandler(messageService, suppressionService, callbackSecret)
+	templateHandler := handlers.NewTemplateHandler(templateService)
+	recipientHandler := handlers.NewRecipientHandler(recipientService)
+	suppressionHandler := handlers.NewSuppressionHandler(suppressionService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	healthHandler := handlers.NewHealthHandler(healthService)
+	configHandler := handlers.NewConfigHandler(configService)
+	authHandler := handlers.NewAuthHandler(authService)
 
-	router := gin.Default()
-	router.Use(cors.Default())
+	router := gin.New()
+	router.Use(gin.Recovery(), cors.Default())
+	router.Use(otelgin.Middleware(serviceName), requestID(), accessLog())
 
 	server := &Server{
-		messageHandler: messageHandler,
-		utilityHandler: utilityHandler,
-		router:         router,
+		messageHandler:     messageHandler,
+		utilityHandler:     utilityHandler,
+		breakerHandler:     breakerHandler,
+		callbackHandler:    callbackHandler,
+		templateHandler:    templateHandler,
+		recipientHandler:   recipientHandler,
+		suppressionHandler: suppressionHandler,
+		scheduleHandler:    scheduleHandler,
+		healthHandler:      healthHandler,
+		configHandler:      configHandler,
+		authHandler:        authHandler,
+		authService:        authService,
+		authEnabled:        authEnabled,
+		metricsHandler:     metricsHandler,
+		router:             router,
+		httpServer:         &http.Server{Handler: router, ReadHeaderTimeout: readHeaderTimeout},
 	}
 
 	server.setupRoutes()
@@ -34,17 +87,80 @@ func NewServer(messageService ports.MessageService, utilityService ports.Utility
 }
 
 func (s *Server) setupRoutes() {
-	s.router.POST("/start", s.messageHandler.StartAutoSender)
-	s.router.POST("/stop", s.messageHandler.StopAutoSender)
-	s.router.GET("/sent", s.messageHandler.GetSentMessages)
-
+	// Probes, metrics, docs and the signed provider callbacks stay open
 	s.router.GET("/ping", s.utilityHandler.Ping)
-	s.router.POST("/seed", s.utilityHandler.SeedSampleMessages)
-	s.router.DELETE("/clear", s.utilityHandler.ClearDatabase)
-
+	s.router.GET("/healthz", s.healthHandler.Healthz)
+	s.router.GET("/readyz", s.healthHandler.Readyz)
+	s.router.GET("/metrics", gin.WrapH(s.metricsHandler))
 	s.router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	s.router.POST("/callbacks/delivery", s.callbackHandler.DeliveryReceipt)
+	s.router.POST("/callbacks/inbound", s.callbackHandler.InboundMessage)
+
+	read := s.router.Group("/", s.access(domain.RoleRead)...)
+	read.GET("/sent", s.messageHandler.GetSentMessages)
+	read.GET("/templates", s.templateHandler.ListTemplates)
+	read.GET("/templates/:id", s.templateHandler.GetTemplate)
+	read.GET("/templates/:id/versions", s.templateHandler.ListTemplateVersions)
+
+	// Recipients, suppressions and schedules are shared by every tenant and hold their numbers;
+	// the sender and breaker status cover every tenant's traffic
+	shared := s.router.Group("/", append(s.access(domain.RoleRead), denyTenantKeys())...)
+	shared.GET("/sender/status", s.messageHandler.GetSenderStatus)
+	shared.GET("/sender/breaker", s.breakerHandler.GetBreakerStatus)
+	shared.GET("/recipients/:phone", s.recipientHandler.GetRecipient)
+	shared.GET("/suppressions", s.suppressionHandler.ListSuppressions)
+	shared.GET("/schedules", s.scheduleHandler.ListSchedules)
+	shared.GET("/schedules/:id", s.scheduleHandler.GetSchedule)
+	shared.GET("/schedules/:id/preview", s.scheduleHandler.PreviewSchedule)
+
+	send := s.router.Group("/", s.access(domain.RoleSend)...)
+	send.POST("/messages", s.messageHandler.EnqueueMessage)
+	send.POST("/messages/preview", s.messageHandler.PreviewTransliteration)
+	send.POST("/messages/:id/cancel", s.messageHandler.CancelMessage)
+
+	operator := s.router.Group("/", s.access(domain.RoleOperator)...)
+	operator.POST("/start", s.messageHandler.StartAutoSender)
+	operator.POST("/stop", s.messageHandler.StopAutoSender)
+	operator.POST("/templates", s.templateHandler.CreateTemplate)
+	operator.PUT("/templates/:id", s.templateHandler.UpdateTemplate)
+	operator.DELETE("/templates/:id", s.templateHandler.DeleteTemplate)
+	operator.PUT("/recipients/:phone", s.recipientHandler.SavePreferences)
+	operator.POST("/suppressions", s.suppressionHandler.Suppress)
+	operator.DELETE("/suppressions/:phone", s.suppressionHandler.Unsuppress)
+	operator.POST("/schedules", s.scheduleHandler.CreateSchedule)
+	operator.PUT("/schedules/:id", s.scheduleHandler.UpdateSchedule)
+	operator.DELETE("/schedules/:id", s.scheduleHandler.DeleteSchedule)
+
+	admin := s.router.Group("/", s.access(domain.RoleAdmin)...)
+	admin.POST("/seed", s.utilityHandler.SeedSampleMessages)
+	admin.DELETE("/clear", s.utilityHandler.ClearDatabase)
+	admin.POST("/admin/config/reload", s.configHandler.ReloadConfig)
+	admin.POST("/admin/keys", s.authHandler.CreateAPIKey)
+	admin.GET("/admin/keys", s.authHandler.ListAPIKeys)
+	admin.DELETE("/admin/keys/:id", s.authHandler.RevokeAPIKey)
+	admin.GET("/admin/audit", s.authHandler.ListAudit)
+}
+
+// access guards a route group: callers need a key whose role includes role. State-changing
+// calls are audited, with no key attached while authentication is disabled.
+func (s *Server) access(role domain.Role) []gin.HandlerFunc {
+	if !s.authEnabled {
+		return []gin.HandlerFunc{audit(s.authService)}
+	}
+	return []gin.HandlerFunc{authenticate(s.authService), audit(s.authService), requireRole(role)}
 }
 
+// Run serves HTTP on addr until Shutdown is called, after which it returns nil
 func (s *Server) Run(addr string) error {
-	return s.router.Run(addr)
+	s.httpServer.Addr = addr
+	if err := s.httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests to finish until ctx ends
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
 }
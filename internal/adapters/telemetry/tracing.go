@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/hasElvin/messenger-svc/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const defaultServiceName = "messenger-svc"
+
+// InitTracing installs the global tracer provider and the W3C trace context propagator.
+// With no exporter configured spans are dropped but trace context still propagates.
+// The returned func flushes buffered spans and should run on shutdown.
+func InitTracing(cfg config.Config) func(context.Context) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Tracing.Exporter {
+	case "":
+		slog.Info("Tracing disabled, no exporter configured")
+		return func(context.Context) error { return nil }
+	case "otlp":
+		opts := []otlptracehttp.Option{}
+		if cfg.Tracing.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Tracing.Endpoint))
+		}
+		if cfg.Tracing.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		log.Fatalf("Unknown tracing exporter %q, expected otlp or stdout", cfg.Tracing.Exporter)
+	}
+	if err != nil {
+		log.Fatalf("Failed to create %s trace exporter: %v", cfg.Tracing.Exporter, err)
+	}
+
+	serviceName := cfg.Tracing.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	ratio := cfg.Tracing.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(serviceName))),
+		// Follow the caller's sampling decision so a provider-side trace is never half recorded
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	slog.Info("Tracing enabled", "exporter", cfg.Tracing.Exporter)
+	return provider.Shutdown
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+// Role is what an API key may do. Roles are ordered and each one includes the ones before it.
+type Role string
+
+const (
+	// RoleRead lists and reads messages, templates, schedules and sender state
+	RoleRead Role = "read"
+	// RoleSend also enqueues messages
+	RoleSend Role = "send"
+	// RoleOperator also runs the sender and manages templates, schedules, recipients and suppressions
+	RoleOperator Role = "operator"
+	// RoleAdmin also manages API keys, reads the audit log, reloads config and seeds or clears data
+	RoleAdmin Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleRead: 1, RoleSend: 2, RoleOperator: 3, RoleAdmin: 4}
+
+// MaxTenantRole is the highest role a tenant key can hold. Higher roles act on state shared
+// by every tenant, like the sender, templates and suppressions.
+const MaxTenantRole = RoleSend
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	return roleRanks[r] > 0
+}
+
+// Allows reports whether r includes required
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && roleRanks[r] >= roleRanks[required]
+}
+
+// APIKey authenticates a client. Only a hash of the secret is stored; Prefix is the start
+// of the key, kept so people can tell their keys apart.
+type APIKey struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	Hash   string `json:"-"`
+	Role   Role   `json:"role"`
+	// TenantID confines the key to one tenant's messages; empty keys act for the whole deployment
+	TenantID   string     `json:"tenant_id,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Active reports whether the key can be used at now
+func (k APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// AuditEntry records a state-changing API call and the key that made it
+type AuditEntry struct {
+	ID uint `json:"id"`
+	// APIKeyID and KeyName are empty when authentication is disabled
+	APIKeyID *uint  `json:"api_key_id,omitempty"`
+	KeyName  string `json:"key_name,omitempty"`
+	Method   string `json:"method"`
+	// Route is the route pattern and Target the resource it addressed, with phone numbers masked
+	Route     string    `json:"route"`
+	Target    string    `json:"target,omitempty"`
+	Status    int       `json:"status"`
+	RequestID string    `json:"request_id,omitempty"`
+	ClientIP  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditFilter narrows an audit log listing; zero fields don't filter
+type AuditFilter struct {
+	APIKeyID uint
+	Limit    int
+}
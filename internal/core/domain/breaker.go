@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerStatus is a point-in-time snapshot of a circuit breaker
+type BreakerStatus struct {
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	RetryAt             *time.Time   `json:"retry_at,omitempty"`
+	Trips               int64        `json:"trips"`
+	Rejected            int64        `json:"rejected"`
+	// Tenants holds the breakers of tenants that have sent messages, by tenant ID
+	Tenants map[string]BreakerStatus `json:"tenants,omitempty"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// StatusCallback is a queued notification telling the originating system about a status change
+type StatusCallback struct {
+	ID            uint       `json:"id"`
+	MessageID     uint       `json:"message_id"`
+	URL           string     `json:"url"`
+	Payload       []byte     `json:"-"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	AbandonedAt   *time.Time `json:"abandoned_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// StatusEvent is the body POSTed to a message's callback URL
+type StatusEvent struct {
+	Event             string    `json:"event"`
+	MessageID         uint      `json:"message_id"`
+	To                string    `json:"to"`
+	Status            Status    `json:"status"`
+	ProviderMessageID string    `json:"provider_message_id,omitempty"`
+	OccurredAt        time.Time `json:"occurred_at"`
+}
+
+const StatusChangedEvent = "message.status_changed"
@@ -0,0 +1,9 @@
+package domain
+
+// ConfigReload reports what reloading the configuration changed
+type ConfigReload struct {
+	// Applied lists the reloadable settings that changed and are now in effect
+	Applied []string `json:"applied"`
+	// RestartRequired lists the sections that changed but only take effect after a restart
+	RestartRequired []string `json:"restart_required"`
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a guarded sender while its circuit breaker rejects calls
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrInvalidMessage is returned when a message is rejected at enqueue time
+var ErrInvalidMessage = errors.New("invalid message")
+
+// ErrMessageNotFound is returned when no message matches the given identifier
+var ErrMessageNotFound = errors.New("message not found")
+
+// ErrInvalidTransition is returned when a message can't move to the requested status from its current one
+var ErrInvalidTransition = errors.New("invalid message status transition")
+
+// ErrStatusConflict is returned when a message's status changed underneath a conditional update
+var ErrStatusConflict = errors.New("message status conflict")
+
+// ErrCacheMiss is returned by the cache when a key isn't present
+var ErrCacheMiss = errors.New("cache miss")
+
+// ErrSuppressionNotFound is returned when a phone number isn't on the suppression list
+var ErrSuppressionNotFound = errors.New("suppression not found")
+
+// DuplicateError is returned at enqueue when an identical message to the same recipient
+// was accepted within the dedupe window
+type DuplicateError struct {
+	// OriginalID is the earlier message, 0 if it was still being stored
+	OriginalID uint
+}
+
+func (e *DuplicateError) Error() string {
+	if e.OriginalID == 0 {
+		return "duplicate message"
+	}
+	return fmt.Sprintf("duplicate of message %d", e.OriginalID)
+}
+
+// ProviderError is returned by a sender when the provider answers with an unexpected HTTP status
+type ProviderError struct {
+	StatusCode int
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// ErrRateLimited is the reason attached to sends held back by outbound rate limits
+var ErrRateLimited = errors.New("outbound rate limit reached")
+
+// DeferredError tells the dispatcher to try a message again later without counting it as a retry
+type DeferredError struct {
+	Reason     error
+	RetryAfter time.Duration
+}
+
+func (e *DeferredError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", e.Reason, e.RetryAfter)
+}
+
+func (e *DeferredError) Unwrap() error {
+	return e.Reason
+}
+
+// ErrTemplateNotFound is returned when no template or template version matches
+var ErrTemplateNotFound = errors.New("template not found")
+
+// ErrInvalidTemplate is returned when a template is malformed or its name is taken
+var ErrInvalidTemplate = errors.New("invalid template")
+
+// ErrRecipientNotFound is returned when no preferences are stored for a phone number
+var ErrRecipientNotFound = errors.New("recipient not found")
+
+// ErrInvalidRecipient is returned when recipient preferences are rejected
+var ErrInvalidRecipient = errors.New("invalid recipient")
+
+// ErrScheduleNotFound is returned when no recurring schedule matches the given identifier
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// ErrInvalidSchedule is returned when a recurring schedule is rejected
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// ErrInvalidConfig is returned when a reloaded configuration fails validation
+var ErrInvalidConfig = errors.New("invalid config")
+
+// ErrUnauthorized is returned when a request carries no API key or one that is unknown,
+// expired or revoked
+var ErrUnauthorized = errors.New("invalid or missing API key")
+
+// ErrAPIKeyNotFound is returned when no API key matches the given identifier
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// ErrInvalidAPIKey is returned when an API key can't be created as requested
+var ErrInvalidAPIKey = errors.New("invalid API key")
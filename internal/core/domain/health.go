@@ -0,0 +1,20 @@
+package domain
+
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "up"
+	HealthDown HealthStatus = "down"
+)
+
+// HealthReport is the outcome of a set of health checks; it is up only if every check is
+type HealthReport struct {
+	Status HealthStatus           `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	Status    HealthStatus `json:"status"`
+	Error     string       `json:"error,omitempty"`
+	LatencyMs int64        `json:"latency_ms"`
+}
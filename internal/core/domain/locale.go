@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale lower-cases a BCP 47 style tag such as tr_TR or az-Latn-AZ,
+// returning "" when it doesn't look like a locale
+func NormalizeLocale(tag string) string {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if !localePattern.MatchString(tag) {
+		return ""
+	}
+	return tag
+}
+
+// LocaleChain lists the locales to try for locale in order: the tag itself, its
+// shorter prefixes (az-latn-az, az-latn, az), their configured fallbacks and finally
+// defaultLocale. Cycles in fallbacks are ignored.
+func LocaleChain(locale string, fallbacks map[string][]string, defaultLocale string) []string {
+	var chain []string
+	var visit func(tag string)
+	visit = func(tag string) {
+		for tag != "" {
+			if slices.Contains(chain, tag) {
+				return
+			}
+			chain = append(chain, tag)
+			for _, fallback := range fallbacks[tag] {
+				visit(NormalizeLocale(fallback))
+			}
+
+			cut := strings.LastIndex(tag, "-")
+			if cut < 0 {
+				return
+			}
+			tag = tag[:cut]
+		}
+	}
+
+	visit(NormalizeLocale(locale))
+	visit(NormalizeLocale(defaultLocale))
+	return chain
+}
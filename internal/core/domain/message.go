@@ -1,20 +1,139 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type Message struct {
-	ID         uint       `json:"id"`
-	To         string     `json:"to"`
-	Content    string     `json:"content"`
-	Status     string     `json:"status"`
-	SentAt     *time.Time `json:"sent_at,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
-	RetryCount int        `json:"retry_count"`
-}
-
-const (
-	StatusPending = "pending"
-	StatusSent    = "sent"
-	StatusFailed  = "failed"
-)
+	ID                uint       `json:"id"`
+	To                string     `json:"to"`
+	CountryCode       string     `json:"country_code,omitempty"`
+	Content           string     `json:"content"`
+	Status            Status     `json:"status"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	RetryCount        int        `json:"retry_count"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at,omitempty"`
+	ProviderMessageID string     `json:"provider_message_id,omitempty"`
+	DeliveredAt       *time.Time `json:"delivered_at,omitempty"`
+	CallbackURL       string     `json:"callback_url,omitempty"`
+	Encoding          string     `json:"encoding,omitempty"`
+	Segments          int        `json:"segments,omitempty"`
+	FailureReason     string     `json:"failure_reason,omitempty"`
+	Transliterate     bool       `json:"transliterate,omitempty"`
+	ParentID          *uint      `json:"parent_id,omitempty"`
+	PartNumber        int        `json:"part_number,omitempty"`
+	PartTotal         int        `json:"part_total,omitempty"`
+	ConcatRef         int        `json:"concat_ref,omitempty"`
+	Parts             []Message  `json:"parts,omitempty"`
+	TemplateID        uint       `json:"template_id,omitempty"`
+	TemplateVersion   int        `json:"template_version,omitempty"`
+	Locale            string     `json:"locale,omitempty"`
+	// Category selects the quiet hours that apply; categories without a window are sent any time
+	Category string `json:"category,omitempty"`
+	// Timezone is the recipient's IANA zone used for quiet hours
+	Timezone string `json:"timezone,omitempty"`
+	// TenantID is the team the message belongs to; its settings override the app-wide ones
+	TenantID string `json:"tenant_id,omitempty"`
+	// DedupeHash identifies the (recipient, content) pair; DuplicateOf is set when the
+	// message was collapsed into an identical one sent within the dedupe window
+	DedupeHash  string `json:"dedupe_hash,omitempty"`
+	DuplicateOf *uint  `json:"duplicate_of,omitempty"`
+	// TraceParent is the W3C trace context of the request that enqueued the message, so
+	// the dispatch that sends it can be linked back to it
+	TraceParent string `json:"-"`
+	// Variables fill the template's placeholders at enqueue; they aren't stored since they may hold codes
+	Variables map[string]string `json:"-"`
+}
+
+// IsMultipart reports whether the message is sent as several numbered parts
+func (m *Message) IsMultipart() bool {
+	return len(m.Parts) > 0
+}
+
+// DeliveryReceipt is a provider report on whether a sent message reached the handset
+type DeliveryReceipt struct {
+	ProviderMessageID string
+	Status            Status
+	ErrorCode         string
+}
+
+// MarkSent records a successful hand-off to the provider
+func (m *Message) MarkSent(providerMessageID string, at time.Time) error {
+	if err := m.transition(StatusSent); err != nil {
+		return err
+	}
+	m.ProviderMessageID = providerMessageID
+	m.SentAt = &at
+	m.UpdatedAt = at
+	return nil
+}
+
+// MarkFailed gives up on a message, recording why
+func (m *Message) MarkFailed(reason string, at time.Time) error {
+	if err := m.transition(StatusFailed); err != nil {
+		return err
+	}
+	m.FailureReason = reason
+	m.UpdatedAt = at
+	return nil
+}
+
+// MarkSuppressed records that the message was withheld because the recipient opted out
+func (m *Message) MarkSuppressed(at time.Time) error {
+	if err := m.transition(StatusSuppressed); err != nil {
+		return err
+	}
+	m.UpdatedAt = at
+	return nil
+}
+
+// MarkDuplicate records that the message was collapsed into an identical earlier message
+func (m *Message) MarkDuplicate(originalID uint, at time.Time) error {
+	if err := m.transition(StatusDuplicate); err != nil {
+		return err
+	}
+	m.DuplicateOf = &originalID
+	m.UpdatedAt = at
+	return nil
+}
+
+// MarkCancelled withdraws a message that hasn't been sent yet
+func (m *Message) MarkCancelled(at time.Time) error {
+	if err := m.transition(StatusCancelled); err != nil {
+		return err
+	}
+	m.UpdatedAt = at
+	return nil
+}
+
+// MarkDelivered records a positive delivery receipt
+func (m *Message) MarkDelivered(at time.Time) error {
+	if err := m.transition(StatusDelivered); err != nil {
+		return err
+	}
+	m.DeliveredAt = &at
+	m.UpdatedAt = at
+	return nil
+}
+
+// MarkUndelivered records a negative delivery receipt with the provider's reason, if any
+func (m *Message) MarkUndelivered(reason string, at time.Time) error {
+	if err := m.transition(StatusUndelivered); err != nil {
+		return err
+	}
+	m.FailureReason = reason
+	m.DeliveredAt = &at
+	m.UpdatedAt = at
+	return nil
+}
+
+func (m *Message) transition(next Status) error {
+	if !m.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: message %d can't move from %s to %s", ErrInvalidTransition, m.ID, m.Status, next)
+	}
+	m.Status = next
+	return nil
+}
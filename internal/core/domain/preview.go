@@ -0,0 +1,16 @@
+package domain
+
+// EncodingSummary describes how a piece of content would go out on the air interface
+type EncodingSummary struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+	Units    int    `json:"units"`
+	Segments int    `json:"segments"`
+}
+
+// TransliterationPreview compares content as written with its GSM-7 transliteration
+type TransliterationPreview struct {
+	Original       EncodingSummary `json:"original"`
+	Transliterated EncodingSummary `json:"transliterated"`
+	SegmentsSaved  int             `json:"segments_saved"`
+}
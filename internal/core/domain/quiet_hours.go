@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuietWindow is a daily span of local time during which a message category must not be
+// sent. Start after End means the window wraps past midnight, e.g. 21:00-09:00.
+type QuietWindow struct {
+	// Start and End are minutes after local midnight
+	Start int
+	End   int
+}
+
+// ParseQuietWindow parses a window from two HH:MM times
+func ParseQuietWindow(start, end string) (QuietWindow, error) {
+	startMinutes, err := parseClock(start)
+	if err != nil {
+		return QuietWindow{}, err
+	}
+	endMinutes, err := parseClock(end)
+	if err != nil {
+		return QuietWindow{}, err
+	}
+	if startMinutes == endMinutes {
+		return QuietWindow{}, fmt.Errorf("quiet window %s-%s is empty", start, end)
+	}
+
+	return QuietWindow{Start: startMinutes, End: endMinutes}, nil
+}
+
+// Contains reports whether the wall clock time of t falls inside the window
+func (w QuietWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// OpensAt returns when the window containing now (in loc) ends, or false when now is
+// outside the window
+func (w QuietWindow) OpensAt(now time.Time, loc *time.Location) (time.Time, bool) {
+	local := now.In(loc)
+	if !w.Contains(local) {
+		return time.Time{}, false
+	}
+
+	opens := time.Date(local.Year(), local.Month(), local.Day(), w.End/60, w.End%60, 0, 0, loc)
+	if !opens.After(local) {
+		// Evening part of a window that wraps past midnight
+		opens = time.Date(local.Year(), local.Month(), local.Day()+1, w.End/60, w.End%60, 0, 0, loc)
+	}
+	return opens, true
+}
+
+func parseClock(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", clock)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
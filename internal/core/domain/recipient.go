@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Recipient holds stored preferences for a phone number, keyed by its E.164 form
+type Recipient struct {
+	Phone     string    `json:"phone"`
+	Locale    string    `json:"locale,omitempty"`
+	Timezone  string    `json:"timezone,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
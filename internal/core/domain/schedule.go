@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Schedule materializes one templated message per recipient at every occurrence of a cron
+// expression, evaluated in Timezone
+type Schedule struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	// Cron is a standard five-field expression or a descriptor such as @weekly
+	Cron       string            `json:"cron"`
+	Timezone   string            `json:"timezone"`
+	TemplateID uint              `json:"template_id"`
+	Variables  map[string]string `json:"variables,omitempty"`
+	Locale     string            `json:"locale,omitempty"`
+	Category   string            `json:"category,omitempty"`
+	Recipients []string          `json:"recipients"`
+	StartAt    *time.Time        `json:"start_at,omitempty"`
+	EndAt      *time.Time        `json:"end_at,omitempty"`
+	// NextRunAt is nil once the schedule has no occurrences left before EndAt
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// SenderStatus is a point-in-time snapshot of the auto-sender
+type SenderStatus struct {
+	Running         bool       `json:"running"`
+	IntervalSeconds int        `json:"interval_seconds,omitempty"`
+	BatchSize       int        `json:"batch_size,omitempty"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	// LastTickAt is when the last pass over pending messages finished; LastBatchSize is how many it fetched
+	LastTickAt    *time.Time `json:"last_tick_at,omitempty"`
+	LastBatchSize int        `json:"last_batch_size"`
+	NextTickAt    *time.Time `json:"next_tick_at,omitempty"`
+	// Counters are reset every time the sender is started
+	Counters SenderCounters `json:"counters"`
+}
+
+// SenderCounters counts dispatch outcomes; a message deferred several times counts each time
+type SenderCounters struct {
+	Sent       int64 `json:"sent"`
+	Failed     int64 `json:"failed"`
+	Retried    int64 `json:"retried"`
+	Deferred   int64 `json:"deferred"`
+	Suppressed int64 `json:"suppressed"`
+	Duplicates int64 `json:"duplicates"`
+}
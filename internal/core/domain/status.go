@@ -0,0 +1,38 @@
+package domain
+
+type Status string
+
+const (
+	StatusPending     Status = "pending"
+	StatusSent        Status = "sent"
+	StatusFailed      Status = "failed"
+	StatusDelivered   Status = "delivered"
+	StatusUndelivered Status = "undelivered"
+	// StatusSuppressed is used for messages held back because the recipient opted out
+	StatusSuppressed Status = "suppressed"
+	// StatusDuplicate is used for messages collapsed into an identical earlier one
+	StatusDuplicate Status = "duplicate"
+	// StatusCancelled is used for messages withdrawn by the client before they were sent
+	StatusCancelled Status = "cancelled"
+)
+
+// transitions lists the statuses each status may move to; anything not listed is rejected
+var transitions = map[Status][]Status{
+	StatusPending: {StatusSent, StatusFailed, StatusSuppressed, StatusDuplicate, StatusCancelled},
+	StatusSent:    {StatusDelivered, StatusUndelivered},
+}
+
+// CanTransitionTo reports whether a message in status s may move to next
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further transitions are allowed from s
+func (s Status) IsTerminal() bool {
+	return len(transitions[s]) == 0
+}
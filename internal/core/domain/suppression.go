@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+type SuppressionSource string
+
+const (
+	// SuppressionManual entries were added through the API
+	SuppressionManual SuppressionSource = "manual"
+	// SuppressionKeyword entries were added by an inbound STOP-style reply
+	SuppressionKeyword SuppressionSource = "keyword"
+)
+
+// Suppression is a phone number that must not be texted
+type Suppression struct {
+	Phone     string            `json:"phone"`
+	Source    SuppressionSource `json:"source"`
+	Reason    string            `json:"reason,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// InboundMessage is a reply from a handset relayed by the provider
+type InboundMessage struct {
+	From string
+	Text string
+}
+
+type InboundAction string
+
+const (
+	InboundOptOut InboundAction = "opt_out"
+	InboundOptIn  InboundAction = "opt_in"
+	InboundIgnore InboundAction = "none"
+)
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// placeholderPattern matches {{name}} with optional inner spaces
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Template is reusable message content with {{variable}} placeholders. Body is written in
+// Locale; Variants hold translations keyed by locale. Every change to the bodies creates a
+// new version and messages record which version and locale they were rendered from.
+type Template struct {
+	ID          uint              `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Version     int               `json:"version"`
+	Locale      string            `json:"locale"`
+	Body        string            `json:"body"`
+	Variants    map[string]string `json:"variants,omitempty"`
+	Variables   []string          `json:"variables"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// TemplateVersion is one immutable revision of a template body in one locale
+type TemplateVersion struct {
+	TemplateID uint      `json:"template_id"`
+	Version    int       `json:"version"`
+	Locale     string    `json:"locale"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RenderedTemplate is message content produced from a template
+type RenderedTemplate struct {
+	Content string
+	Version int
+	Locale  string
+}
+
+// ParsePlaceholders returns the distinct variable names used in body in order of first use,
+// rejecting stray or malformed braces
+func ParsePlaceholders(body string) ([]string, error) {
+	if strings.TrimSpace(body) == "" {
+		return nil, fmt.Errorf("%w: body is required", ErrInvalidTemplate)
+	}
+
+	rest := placeholderPattern.ReplaceAllString(body, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return nil, fmt.Errorf("%w: placeholders must look like {{name}}", ErrInvalidTemplate)
+	}
+
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(body, -1) {
+		if !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+	return names, nil
+}
+
+// Variant picks the body for the first locale in chain the template has,
+// falling back to the template's own locale
+func (t Template) Variant(chain []string) (locale, body string) {
+	for _, candidate := range chain {
+		if candidate == t.Locale {
+			return t.Locale, t.Body
+		}
+		if variant, ok := t.Variants[candidate]; ok {
+			return candidate, variant
+		}
+	}
+	return t.Locale, t.Body
+}
+
+// Render substitutes vars into the variant chosen by chain. Every placeholder of that
+// variant needs a value; variables it doesn't use are ignored.
+func (t Template) Render(chain []string, vars map[string]string) (RenderedTemplate, error) {
+	locale, body := t.Variant(chain)
+
+	names, err := ParsePlaceholders(body)
+	if err != nil {
+		return RenderedTemplate{}, err
+	}
+
+	var missing []string
+	for _, name := range names {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return RenderedTemplate{}, fmt.Errorf("%w: template %q (%s) is missing variables %s",
+			ErrInvalidMessage, t.Name, locale, strings.Join(missing, ", "))
+	}
+
+	content := placeholderPattern.ReplaceAllStringFunc(body, func(placeholder string) string {
+		return vars[placeholderPattern.FindStringSubmatch(placeholder)[1]]
+	})
+	return RenderedTemplate{Content: content, Version: t.Version, Locale: locale}, nil
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestIDKey struct{}
+
+// level is shared by every handler Setup installs so SetLevel can change it at runtime
+var level = new(slog.LevelVar)
+
+// Setup installs a slog default logger writing format ("json" or "text") to w at levelName
+// ("debug", "info", "warn" or "error"). The standard log package is routed through it too.
+func Setup(w io.Writer, levelName, format string) error {
+	if err := SetLevel(levelName); err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q, expected json or text", format)
+	}
+
+	slog.SetDefault(slog.New(contextHandler{handler}))
+	return nil
+}
+
+// SetLevel changes the minimum level logged ("debug", "info", "warn" or "error", default info)
+func SetLevel(levelName string) error {
+	if levelName == "" {
+		levelName = "info"
+	}
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(levelName)); err != nil {
+		return fmt.Errorf("invalid log level %q", levelName)
+	}
+	level.Set(lvl)
+	return nil
+}
+
+// WithRequestID stores the API request ID so log lines written under ctx carry it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the API request ID stored in ctx, if any
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// ForMessage returns a logger that tags every line with the message ID, masked
+// recipient and the attempt being made
+func ForMessage(msg domain.Message) *slog.Logger {
+	return slog.Default().With(
+		slog.Uint64("message_id", uint64(msg.ID)),
+		slog.String("recipient", MaskPhone(msg.To)),
+		slog.Int("attempt", msg.RetryCount+1),
+	)
+}
+
+// MaskPhone keeps the country code prefix and the last two digits of a phone number,
+// enough to tell numbers apart in logs without recording them
+func MaskPhone(number string) string {
+	const keepStart, keepEnd = 3, 2
+	runes := []rune(number)
+	if len(runes) <= keepStart+keepEnd {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:keepStart]) + strings.Repeat("*", len(runes)-keepStart-keepEnd) +
+		string(runes[len(runes)-keepEnd:])
+}
+
+// contextHandler adds the request ID and trace IDs found in the context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()),
+			slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,126 @@
+package phone
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// regionMeta is the subset of a country's numbering plan needed to validate SMS recipients
+type regionMeta struct {
+	region      string
+	callingCode string
+	// trunkPrefix is dialled before national numbers inside the country, e.g. 0 in 0555...
+	trunkPrefix string
+	// exit is the international call prefix; empty means 00
+	exit string
+	// lengths are the valid national significant number lengths for mobile numbers
+	lengths []int
+	// leading restricts the first digit of national numbers; empty allows any
+	leading string
+	// mobilePrefixes identify mobile ranges; empty means the plan doesn't distinguish them
+	mobilePrefixes []string
+	// timezone is the IANA zone most of the country's subscribers live in
+	timezone string
+}
+
+var regions = indexByRegion([]regionMeta{
+	{region: "AE", timezone: "Asia/Dubai", callingCode: "971", trunkPrefix: "0", lengths: []int{9}, mobilePrefixes: []string{"5"}},
+	{region: "AZ", timezone: "Asia/Baku", callingCode: "994", trunkPrefix: "0", lengths: []int{9},
+		mobilePrefixes: []string{"10", "50", "51", "55", "60", "70", "77", "99"}},
+	{region: "DE", timezone: "Europe/Berlin", callingCode: "49", trunkPrefix: "0", lengths: []int{10, 11},
+		mobilePrefixes: []string{"15", "16", "17"}},
+	{region: "FR", timezone: "Europe/Paris", callingCode: "33", trunkPrefix: "0", lengths: []int{9}, mobilePrefixes: []string{"6", "7"}},
+	{region: "GB", timezone: "Europe/London", callingCode: "44", trunkPrefix: "0", lengths: []int{10}, mobilePrefixes: []string{"7"}},
+	{region: "GE", timezone: "Asia/Tbilisi", callingCode: "995", trunkPrefix: "0", lengths: []int{9}, mobilePrefixes: []string{"5"}},
+	{region: "NL", timezone: "Europe/Amsterdam", callingCode: "31", trunkPrefix: "0", lengths: []int{9}, mobilePrefixes: []string{"6"}},
+	{region: "SA", timezone: "Asia/Riyadh", callingCode: "966", trunkPrefix: "0", lengths: []int{9}, mobilePrefixes: []string{"5"}},
+	{region: "TR", timezone: "Europe/Istanbul", callingCode: "90", trunkPrefix: "0", lengths: []int{10}, mobilePrefixes: []string{"5"}},
+	{region: "UA", timezone: "Europe/Kyiv", callingCode: "380", trunkPrefix: "0", lengths: []int{9},
+		mobilePrefixes: []string{"39", "50", "63", "66", "67", "68", "73", "9"}},
+	{region: "US", timezone: "America/New_York", callingCode: "1", trunkPrefix: "1", exit: "011",
+		lengths: []int{10}, leading: "23456789"},
+})
+
+var byCallingCode = indexByCallingCode(regions)
+
+// Timezone is the IANA zone assumed for numbers of region when nothing better is known.
+// For countries spanning several zones it is the most populous one.
+func Timezone(region string) string {
+	return regions[strings.ToUpper(region)].timezone
+}
+
+// Supported reports whether region has numbering plan metadata
+func Supported(region string) bool {
+	_, ok := regions[strings.ToUpper(region)]
+	return ok
+}
+
+func (m regionMeta) exitPrefix() string {
+	if m.exit == "" {
+		return "00"
+	}
+	return m.exit
+}
+
+// nationalNumber strips an optional trunk prefix and checks what is left is a plausible
+// national significant number
+func (m regionMeta) nationalNumber(digits string) (string, bool) {
+	if m.trunkPrefix != "" && strings.HasPrefix(digits, m.trunkPrefix) {
+		if stripped := digits[len(m.trunkPrefix):]; m.validNational(stripped) {
+			return stripped, true
+		}
+	}
+	if m.validNational(digits) {
+		return digits, true
+	}
+	return "", false
+}
+
+func (m regionMeta) validNational(national string) bool {
+	if !slices.Contains(m.lengths, len(national)) {
+		return false
+	}
+	// A national significant number never starts with the trunk prefix itself
+	if m.trunkPrefix != "" && strings.HasPrefix(national, m.trunkPrefix) {
+		return false
+	}
+	return m.leading == "" || strings.ContainsRune(m.leading, rune(national[0]))
+}
+
+func (m regionMeta) number(national string) (Number, error) {
+	numberType := Unknown
+	if len(m.mobilePrefixes) > 0 {
+		if !slices.ContainsFunc(m.mobilePrefixes, func(prefix string) bool {
+			return strings.HasPrefix(national, prefix)
+		}) {
+			return Number{}, fmt.Errorf("%w: +%s%s", ErrNotMobile, m.callingCode, national)
+		}
+		numberType = Mobile
+	}
+
+	return Number{
+		E164:        "+" + m.callingCode + national,
+		Region:      m.region,
+		CallingCode: m.callingCode,
+		National:    national,
+		Type:        numberType,
+		Timezone:    m.timezone,
+	}, nil
+}
+
+func indexByRegion(metas []regionMeta) map[string]regionMeta {
+	index := make(map[string]regionMeta, len(metas))
+	for _, meta := range metas {
+		index[meta.region] = meta
+	}
+	return index
+}
+
+func indexByCallingCode(metas map[string]regionMeta) map[string]regionMeta {
+	index := make(map[string]regionMeta, len(metas))
+	for _, meta := range metas {
+		index[meta.callingCode] = meta
+	}
+	return index
+}
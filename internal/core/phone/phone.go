@@ -0,0 +1,131 @@
+// Package phone parses the many ways clients write phone numbers and normalizes them
+// to E.164, validating length and number type against per-country metadata.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidNumber      = errors.New("invalid phone number")
+	ErrUnsupportedCountry = errors.New("unsupported country")
+	ErrNotMobile          = errors.New("number is not a mobile number")
+)
+
+type Type string
+
+const (
+	Mobile Type = "mobile"
+	// Unknown is used where the numbering plan doesn't tell mobile and fixed lines apart
+	Unknown Type = "unknown"
+)
+
+// Number is a validated phone number
+type Number struct {
+	// E164 is the normalized form, e.g. +905551234567
+	E164 string
+	// Region is the ISO 3166-1 alpha-2 country the number belongs to
+	Region string
+	// CallingCode is the ITU country calling code without the plus sign
+	CallingCode string
+	// National is the national significant number, without trunk prefix
+	National string
+	Type     Type
+	// Timezone is the country's IANA zone, only a guess for countries spanning several
+	Timezone string
+}
+
+// Parse normalizes raw to E.164. Numbers without an international prefix are read as
+// national numbers of defaultRegion; an empty defaultRegion only accepts international input.
+func Parse(raw, defaultRegion string) (Number, error) {
+	digits, international, err := clean(raw)
+	if err != nil {
+		return Number{}, err
+	}
+
+	defaultRegion = strings.ToUpper(strings.TrimSpace(defaultRegion))
+	home, hasHome := regions[defaultRegion]
+	if defaultRegion != "" && !hasHome {
+		return Number{}, fmt.Errorf("%w: default region %q", ErrUnsupportedCountry, defaultRegion)
+	}
+
+	if !international && hasHome {
+		// 00 or the region's own exit code, e.g. 00905551234567 or 011...
+		if exit := home.exitPrefix(); strings.HasPrefix(digits, exit) {
+			digits, international = digits[len(exit):], true
+		}
+	}
+
+	if international {
+		return parseInternational(digits)
+	}
+	if !hasHome {
+		return Number{}, fmt.Errorf("%w: %q has no country code and no default region is set", ErrInvalidNumber, raw)
+	}
+
+	// 0555..., 555... or 90555... written without the plus sign
+	if national, ok := home.nationalNumber(digits); ok {
+		return home.number(national)
+	}
+	if strings.HasPrefix(digits, home.callingCode) {
+		if national, ok := home.nationalNumber(digits[len(home.callingCode):]); ok {
+			return home.number(national)
+		}
+	}
+
+	return Number{}, fmt.Errorf("%w: %q is not a valid %s number", ErrInvalidNumber, raw, home.region)
+}
+
+// Normalize is Parse for callers that only need the E.164 form
+func Normalize(raw, defaultRegion string) (string, error) {
+	number, err := Parse(raw, defaultRegion)
+	if err != nil {
+		return "", err
+	}
+	return number.E164, nil
+}
+
+// clean strips formatting characters and reports whether the number carried a leading plus
+func clean(raw string) (string, bool, error) {
+	raw = strings.TrimSpace(raw)
+	international := strings.HasPrefix(raw, "+")
+	raw = strings.TrimPrefix(raw, "+")
+
+	var b strings.Builder
+	for _, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ', r == '-', r == '.', r == '(', r == ')', r == '/', r == '\u00a0':
+		default:
+			return "", false, fmt.Errorf("%w: unexpected character %q", ErrInvalidNumber, r)
+		}
+	}
+
+	digits := b.String()
+	if digits == "" {
+		return "", false, fmt.Errorf("%w: no digits", ErrInvalidNumber)
+	}
+	return digits, international, nil
+}
+
+func parseInternational(digits string) (Number, error) {
+	// Calling codes are prefix-free, so at most one of the 1-3 digit prefixes matches
+	for size := 1; size <= 3 && size < len(digits); size++ {
+		meta, ok := byCallingCode[digits[:size]]
+		if !ok {
+			continue
+		}
+		if national, ok := meta.nationalNumber(digits[size:]); ok {
+			return meta.number(national)
+		}
+		return Number{}, fmt.Errorf("%w: +%s is not a valid %s number", ErrInvalidNumber, digits, meta.region)
+	}
+
+	if len(digits) < 8 || len(digits) > 15 {
+		return Number{}, fmt.Errorf("%w: +%s", ErrInvalidNumber, digits)
+	}
+	return Number{}, fmt.Errorf("%w: +%s", ErrUnsupportedCountry, digits)
+}
@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"time"
+)
+
+// APIKeyRepository defines the interface for API key persistence
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *domain.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uint, at time.Time) error
+	TouchAPIKey(ctx context.Context, id uint, at time.Time) error
+}
+
+// AuditRepository defines the interface for the audit log
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry *domain.AuditEntry) error
+	ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
+
+// AuthService defines the interface for API key authentication and the audit log
+type AuthService interface {
+	// Authenticate returns the active key matching rawKey, or domain.ErrUnauthorized
+	Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error)
+	// CreateKey stores a new key and returns it with its secret, which is never shown again.
+	// A key with a tenantID is confined to that configured tenant.
+	CreateKey(ctx context.Context, name string, role domain.Role, tenantID string,
+		expiresAt *time.Time) (*domain.APIKey, string, error)
+	// EnsureKey stores rawKey with role under name unless it already exists, for bootstrapping
+	EnsureKey(ctx context.Context, name, rawKey string, role domain.Role) error
+	ListKeys(ctx context.Context) ([]domain.APIKey, error)
+	// HasAdminKey reports whether an active admin key exists, without which no key can be created
+	HasAdminKey(ctx context.Context) (bool, error)
+	RevokeKey(ctx context.Context, id uint) error
+	Record(ctx context.Context, entry domain.AuditEntry) error
+	ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
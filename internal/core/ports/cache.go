@@ -1,9 +1,17 @@
 package ports
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // CacheService defines the interface for caching operations
 type CacheService interface {
 	Set(ctx context.Context, key, value string) error
+	SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error
+	// SetIfAbsent sets key only if it doesn't exist yet, reporting whether it did
+	SetIfAbsent(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Get returns domain.ErrCacheMiss when key isn't cached
 	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, key string) error
 }
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"time"
+)
+
+// CallbackRepository defines the interface for the status callback queue
+type CallbackRepository interface {
+	EnqueueCallback(ctx context.Context, callback *domain.StatusCallback) error
+	// ClaimDueCallbacks leases up to limit due callbacks so other replicas skip them until lease expires
+	ClaimDueCallbacks(ctx context.Context, limit int, lease time.Duration) ([]domain.StatusCallback, error)
+	MarkCallbackDelivered(ctx context.Context, id uint) error
+	RescheduleCallback(ctx context.Context, id uint, next time.Time, lastErr string) error
+	MarkCallbackAbandoned(ctx context.Context, id uint, lastErr string) error
+}
+
+// CallbackPoster defines the interface for delivering a signed callback body to a URL
+type CallbackPoster interface {
+	Post(ctx context.Context, url string, body []byte) error
+}
+
+// StatusNotifier is told about every persisted message status change
+type StatusNotifier interface {
+	Notify(ctx context.Context, msg domain.Message) error
+}
+
+// CallbackService delivers queued status callbacks independently of SMS dispatch
+type CallbackService interface {
+	StatusNotifier
+	Run(ctx context.Context, interval time.Duration)
+	DispatchDueCallbacks(ctx context.Context)
+}
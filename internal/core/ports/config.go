@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+)
+
+// ConfigProvider returns the configuration currently in effect, which changes on reload
+type ConfigProvider interface {
+	Current() config.Config
+}
+
+// ConfigService holds the running configuration and swaps in reloadable settings at runtime
+type ConfigService interface {
+	ConfigProvider
+	// Reload reads the configuration again and, if it is valid, applies the settings that can
+	// change without a restart. An invalid configuration leaves the current one untouched.
+	Reload(ctx context.Context) (domain.ConfigReload, error)
+}
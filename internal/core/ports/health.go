@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+)
+
+// HealthCheck probes one dependency, returning nil while it is usable
+type HealthCheck interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// SenderMonitor reports the state of the auto-sender
+type SenderMonitor interface {
+	SenderStatus() domain.SenderStatus
+}
+
+// HealthService aggregates health checks for the orchestrator's probes
+type HealthService interface {
+	// Liveness reports whether the process is working at all and should be restarted if not
+	Liveness(ctx context.Context) domain.HealthReport
+	// Readiness reports whether the process can serve traffic right now
+	Readiness(ctx context.Context) domain.HealthReport
+}
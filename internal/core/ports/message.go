@@ -4,15 +4,26 @@ import (
 	"context"
 	"github.com/hasElvin/messenger-svc/config"
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"time"
 )
 
 // MessageRepository defines the interface for message persistence
 type MessageRepository interface {
-	GetPendingMessages(ctx context.Context, limit, messageCharLimit, maxRetries int) ([]domain.Message, error)
-	UpdateMessageStatus(ctx context.Context, id uint, status string) error
-	GetSentMessages(ctx context.Context) ([]domain.Message, error)
+	GetPendingMessages(ctx context.Context, limit, maxRetries int) ([]domain.Message, error)
+	// UpdateMessageStatus persists msg's status and timestamps only if the stored status is still from
+	UpdateMessageStatus(ctx context.Context, msg domain.Message, from domain.Status) error
+	// GetMessageByProviderID returns the message the provider ID was issued for. A multipart
+	// message shares its first part's ID and is returned in its place, with its parts.
+	GetMessageByProviderID(ctx context.Context, providerMessageID string) (*domain.Message, error)
+	// GetMessage returns a logical message with its parts; parts themselves aren't found by ID
+	GetMessage(ctx context.Context, id uint) (*domain.Message, error)
+	// GetSentMessages lists sent messages of tenantID, delivered or not, or of every tenant when it is empty
+	GetSentMessages(ctx context.Context, tenantID string) ([]domain.Message, error)
 	CreateMessage(ctx context.Context, message *domain.Message) error
+	// CountPendingMessages counts messages still waiting to be sent, including deferred ones
+	CountPendingMessages(ctx context.Context, maxRetries int) (int64, error)
 	IncrementRetryCount(ctx context.Context, id uint) error
+	DeferMessage(ctx context.Context, id uint, until time.Time) error
 	SeedSampleMessages() error
 	ClearDatabase() error
 }
@@ -22,13 +33,35 @@ type MessageSender interface {
 	Send(ctx context.Context, message domain.Message) (string, error)
 }
 
+// RateLimiter reserves outbound capacity for a recipient, returning how long
+// to wait when the global or per-recipient limit has been reached
+type RateLimiter interface {
+	Reserve(ctx context.Context, recipient string) (time.Duration, error)
+}
+
+// CircuitBreaker is a MessageSender guarded by a circuit breaker
+type CircuitBreaker interface {
+	MessageSender
+	Status() domain.BreakerStatus
+}
+
 // MessageService defines the interface for message business logic
 type MessageService interface {
-	StartAutoSender(ctx context.Context, intervalSeconds int) error
+	SenderMonitor
+	StartAutoSender(ctx context.Context, intervalSeconds, batchSize int) error
 	StopAutoSender(ctx context.Context) error
-	GetSentMessages(ctx context.Context) ([]domain.Message, error)
+	// Drain waits for the auto-sender to exit and its in-flight send to be recorded, until ctx ends
+	Drain(ctx context.Context) error
+	// GetSentMessages lists sent messages of tenantID, delivered or not, or of every tenant when it is empty
+	GetSentMessages(ctx context.Context, tenantID string) ([]domain.Message, error)
+	// CancelMessage withdraws a pending message. A tenant only finds its own messages;
+	// an empty tenantID acts for the whole deployment.
+	CancelMessage(ctx context.Context, id uint, tenantID string) (*domain.Message, error)
 	SendPendingMessages(ctx context.Context, cfg *config.Config)
 	SendMessage(ctx context.Context, msg domain.Message) error
+	EnqueueMessage(ctx context.Context, msg *domain.Message) error
+	PreviewTransliteration(content string) domain.TransliterationPreview
+	ProcessDeliveryReceipt(ctx context.Context, receipt domain.DeliveryReceipt) error
 }
 
 // UtilityService defines some utility tools for testing the app
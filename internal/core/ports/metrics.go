@@ -0,0 +1,14 @@
+package ports
+
+import "time"
+
+// DispatchMetrics records what the auto-sender does with messages
+type DispatchMetrics interface {
+	MessageSent()
+	// MessageFailed counts a message given up on because of err
+	MessageFailed(err error)
+	// MessageRetried counts a failed attempt that will be tried again
+	MessageRetried(err error)
+	ObserveTick(duration time.Duration)
+	SetSenderRunning(running bool)
+}
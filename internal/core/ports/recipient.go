@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+)
+
+// RecipientRepository defines the interface for stored recipient preferences
+type RecipientRepository interface {
+	GetRecipient(ctx context.Context, phone string) (*domain.Recipient, error)
+	UpsertRecipient(ctx context.Context, recipient *domain.Recipient) error
+}
+
+// RecipientService defines the interface for managing recipient preferences
+type RecipientService interface {
+	GetRecipient(ctx context.Context, phone string) (*domain.Recipient, error)
+	SavePreferences(ctx context.Context, recipient *domain.Recipient) error
+}
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"time"
+)
+
+// ScheduleRepository defines the interface for recurring schedule persistence
+type ScheduleRepository interface {
+	CreateSchedule(ctx context.Context, schedule *domain.Schedule) error
+	GetSchedule(ctx context.Context, id uint) (*domain.Schedule, error)
+	ListSchedules(ctx context.Context) ([]domain.Schedule, error)
+	UpdateSchedule(ctx context.Context, schedule *domain.Schedule) error
+	DeleteSchedule(ctx context.Context, id uint) error
+	ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]domain.Schedule, error)
+	// AdvanceSchedule moves a schedule from the occurrence due to next only if it is still at due,
+	// reporting whether this caller won the occurrence
+	AdvanceSchedule(ctx context.Context, id uint, due time.Time, next *time.Time) (bool, error)
+}
+
+// MessageEnqueuer accepts messages for dispatch
+type MessageEnqueuer interface {
+	EnqueueMessage(ctx context.Context, msg *domain.Message) error
+}
+
+// ScheduleService defines the interface for recurring message schedules
+type ScheduleService interface {
+	CreateSchedule(ctx context.Context, schedule *domain.Schedule) error
+	GetSchedule(ctx context.Context, id uint) (*domain.Schedule, error)
+	ListSchedules(ctx context.Context) ([]domain.Schedule, error)
+	UpdateSchedule(ctx context.Context, schedule *domain.Schedule) error
+	DeleteSchedule(ctx context.Context, id uint) error
+	// PreviewRuns returns the next count occurrences of a schedule after now
+	PreviewRuns(ctx context.Context, id uint, count int) ([]time.Time, error)
+	Run(ctx context.Context, interval time.Duration)
+	RunDueSchedules(ctx context.Context)
+}
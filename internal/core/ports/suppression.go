@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+)
+
+// SuppressionRepository defines the interface for the opt-out list
+type SuppressionRepository interface {
+	// AddSuppression keeps the existing entry if phone is already suppressed
+	AddSuppression(ctx context.Context, suppression *domain.Suppression) error
+	GetSuppression(ctx context.Context, phone string) (*domain.Suppression, error)
+	RemoveSuppression(ctx context.Context, phone string) error
+	ListSuppressions(ctx context.Context) ([]domain.Suppression, error)
+}
+
+// SuppressionChecker tells the dispatcher whether a recipient opted out
+type SuppressionChecker interface {
+	IsSuppressed(ctx context.Context, phone string) (bool, error)
+}
+
+// SuppressionService defines the interface for managing opt-outs
+type SuppressionService interface {
+	SuppressionChecker
+	Suppress(ctx context.Context, suppression *domain.Suppression) error
+	Unsuppress(ctx context.Context, phone string) error
+	ListSuppressions(ctx context.Context) ([]domain.Suppression, error)
+	// HandleInbound opts the sender out or back in when the reply is a keyword
+	HandleInbound(ctx context.Context, inbound domain.InboundMessage) (domain.InboundAction, error)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+)
+
+// TemplateRepository defines the interface for versioned template persistence
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, template *domain.Template) error
+	// GetTemplate returns the given version of a template, or the latest one when version is 0
+	GetTemplate(ctx context.Context, id uint, version int) (*domain.Template, error)
+	ListTemplates(ctx context.Context) ([]domain.Template, error)
+	// UpdateTemplate stores changed bodies as a new version and sets template.Version to it
+	UpdateTemplate(ctx context.Context, template *domain.Template) error
+	DeleteTemplate(ctx context.Context, id uint) error
+	ListTemplateVersions(ctx context.Context, id uint) ([]domain.TemplateVersion, error)
+}
+
+// TemplateRenderer renders a template version in the best available variant for locale
+type TemplateRenderer interface {
+	Render(ctx context.Context, id uint, version int, locale string,
+		vars map[string]string) (domain.RenderedTemplate, error)
+}
+
+// TemplateService defines the interface for template management
+type TemplateService interface {
+	TemplateRenderer
+	CreateTemplate(ctx context.Context, template *domain.Template) error
+	GetTemplate(ctx context.Context, id uint, version int) (*domain.Template, error)
+	ListTemplates(ctx context.Context) ([]domain.Template, error)
+	UpdateTemplate(ctx context.Context, template *domain.Template) error
+	DeleteTemplate(ctx context.Context, id uint) error
+	ListTemplateVersions(ctx context.Context, id uint) ([]domain.TemplateVersion, error)
+}
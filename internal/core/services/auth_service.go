@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+const (
+	// apiKeyPrefix marks generated keys so they are easy to spot in code and secret scanners
+	apiKeyPrefix = "msk_"
+	// apiKeyDisplayLength is how much of a generated key is kept in clear to identify it
+	apiKeyDisplayLength = 12
+	// minAPIKeyLength keeps keys supplied from outside, like the bootstrap key, guess-proof
+	minAPIKeyLength = 32
+	// keyTouchInterval limits last_used_at writes to one per key per interval
+	keyTouchInterval  = time.Minute
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type authService struct {
+	keys    ports.APIKeyRepository
+	audit   ports.AuditRepository
+	tenants ports.ConfigProvider
+}
+
+// NewAuthService creates the API key and audit log service. Keys are random, so a plain
+// SHA-256 is enough to store them; there is no low-entropy password to slow brute force on.
+// Tenant keys are checked against the tenants in config.
+func NewAuthService(keys ports.APIKeyRepository, audit ports.AuditRepository,
+	tenants ports.ConfigProvider) ports.AuthService {
+	return &authService{
+		keys:    keys,
+		audit:   audit,
+		tenants: tenants,
+	}
+}
+
+func (s *authService) Authenticate(ctx context.Context, rawKey string) (*domain.APIKey, error) {
+	if rawKey == "" {
+		return nil, domain.ErrUnauthorized
+	}
+
+	key, err := s.keys.GetAPIKeyByHash(ctx, hashAPIKey(rawKey))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, domain.ErrUnauthorized
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= keyTouchInterval {
+		// Last use is informational; failing to record it mustn't fail the request
+		if err := s.keys.TouchAPIKey(ctx, key.ID, now); err != nil {
+			slog.WarnContext(ctx, "Failed to record API key use", "api_key_id", key.ID, "error", err)
+		}
+	}
+	return key, nil
+}
+
+func (s *authService) CreateKey(ctx context.Context, name string, role domain.Role, tenantID string,
+	expiresAt *time.Time) (*domain.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: name is required", domain.ErrInvalidAPIKey)
+	}
+	if !role.Valid() {
+		return nil, "", fmt.Errorf("%w: unknown role %q", domain.ErrInvalidAPIKey, role)
+	}
+	if tenantID != "" {
+		if !s.tenants.Current().HasTenant(tenantID) {
+			return nil, "", fmt.Errorf("%w: unknown tenant %q", domain.ErrInvalidAPIKey, tenantID)
+		}
+		if !domain.MaxTenantRole.Allows(role) {
+			return nil, "", fmt.Errorf("%w: tenant keys can't have a role above %s",
+				domain.ErrInvalidAPIKey, domain.MaxTenantRole)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", fmt.Errorf("%w: expires_at must be in the future", domain.ErrInvalidAPIKey)
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	rawKey := apiKeyPrefix + hex.EncodeToString(secret)
+
+	key := domain.APIKey{
+		Name:      name,
+		Prefix:    rawKey[:apiKeyDisplayLength],
+		Hash:      hashAPIKey(rawKey),
+		Role:      role,
+		TenantID:  tenantID,
+		ExpiresAt: expiresAt,
+	}
+	if err := s.keys.CreateAPIKey(ctx, &key); err != nil {
+		return nil, "", err
+	}
+
+	slog.InfoContext(ctx, "API key created", "api_key_id", key.ID, "name", key.Name, "role", key.Role,
+		"tenant_id", key.TenantID)
+	return &key, rawKey, nil
+}
+
+// EnsureKey is idempotent, and a key that was revoked stays revoked
+func (s *authService) EnsureKey(ctx context.Context, name, rawKey string, role domain.Role) error {
+	if len(rawKey) < minAPIKeyLength {
+		return fmt.Errorf("%w: must be at least %d characters", domain.ErrInvalidAPIKey, minAPIKeyLength)
+	}
+
+	hash := hashAPIKey(rawKey)
+	existing, err := s.keys.GetAPIKeyByHash(ctx, hash)
+	if err == nil {
+		if existing.RevokedAt != nil {
+			slog.WarnContext(ctx, "Configured API key was revoked, not restoring it", "name", existing.Name)
+		}
+		return nil
+	}
+	if !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return err
+	}
+
+	// Only a few characters of a chosen key are shown, as it may be shorter than a generated one
+	key := domain.APIKey{Name: name, Prefix: rawKey[:4], Hash: hash, Role: role}
+	return s.keys.CreateAPIKey(ctx, &key)
+}
+
+func (s *authService) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	return s.keys.ListAPIKeys(ctx)
+}
+
+func (s *authService) HasAdminKey(ctx context.Context) (bool, error) {
+	keys, err := s.keys.ListAPIKeys(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, key := range keys {
+		if key.Role == domain.RoleAdmin && key.TenantID == "" && key.Active(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *authService) RevokeKey(ctx context.Context, id uint) error {
+	if err := s.keys.RevokeAPIKey(ctx, id, time.Now()); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "API key revoked", "api_key_id", id)
+	return nil
+}
+
+func (s *authService) Record(ctx context.Context, entry domain.AuditEntry) error {
+	return s.audit.RecordAudit(ctx, &entry)
+}
+
+func (s *authService) ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	return s.audit.ListAudit(ctx, filter)
+}
+
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+const (
+	callbackBatchSize  = 20
+	callbackLease      = time.Minute
+	callbackBaseDelay  = 5 * time.Second
+	callbackMaxBackoff = time.Hour
+)
+
+type callbackService struct {
+	repo        ports.CallbackRepository
+	poster      ports.CallbackPoster
+	maxAttempts int
+}
+
+func NewCallbackService(repo ports.CallbackRepository, poster ports.CallbackPoster,
+	maxAttempts int) ports.CallbackService {
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+
+	return &callbackService{
+		repo:        repo,
+		poster:      poster,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Notify queues a status event for messages that asked for one; delivery happens in Run
+func (s *callbackService) Notify(ctx context.Context, msg domain.Message) error {
+	if msg.CallbackURL == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(domain.StatusEvent{
+		Event:             domain.StatusChangedEvent,
+		MessageID:         msg.ID,
+		To:                msg.To,
+		Status:            msg.Status,
+		ProviderMessageID: msg.ProviderMessageID,
+		OccurredAt:        time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal status event: %w", err)
+	}
+
+	return s.repo.EnqueueCallback(ctx, &domain.StatusCallback{
+		MessageID:     msg.ID,
+		URL:           msg.CallbackURL,
+		Payload:       payload,
+		NextAttemptAt: time.Now(),
+	})
+}
+
+func (s *callbackService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.DispatchDueCallbacks(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *callbackService) DispatchDueCallbacks(ctx context.Context) {
+	callbacks, err := s.repo.ClaimDueCallbacks(ctx, callbackBatchSize, callbackLease)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to claim status callbacks", "error", err)
+		return
+	}
+
+	for _, callback := range callbacks {
+		logger := slog.Default().With("callback_id", callback.ID, "message_id", callback.MessageID,
+			"attempt", callback.Attempts)
+
+		err := s.poster.Post(ctx, callback.URL, callback.Payload)
+		if err == nil {
+			if err := s.repo.MarkCallbackDelivered(ctx, callback.ID); err != nil {
+				logger.ErrorContext(ctx, "Failed to mark callback delivered", "error", err)
+			}
+			continue
+		}
+
+		// Attempts was already incremented when the callback was claimed
+		if callback.Attempts >= s.maxAttempts {
+			logger.WarnContext(ctx, "Abandoning callback, out of attempts", "error", err)
+			if err := s.repo.MarkCallbackAbandoned(ctx, callback.ID, err.Error()); err != nil {
+				logger.ErrorContext(ctx, "Failed to abandon callback", "error", err)
+			}
+			continue
+		}
+
+		next := time.Now().Add(CallbackBackoff(callback.Attempts))
+		logger.WarnContext(ctx, "Callback failed, retrying", "retry_at", next, "error", err)
+		if err := s.repo.RescheduleCallback(ctx, callback.ID, next, err.Error()); err != nil {
+			logger.ErrorContext(ctx, "Failed to reschedule callback", "error", err)
+		}
+	}
+}
+
+// CallbackBackoff returns the exponential delay before the next delivery attempt
+func CallbackBackoff(attempts int) time.Duration {
+	delay := callbackBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= callbackMaxBackoff {
+			return callbackMaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type circuitBreaker struct {
+	next             ports.MessageSender
+	failureThreshold int
+	openTimeout      time.Duration
+	halfOpenMax      int
+	// logAttrs tell apart the breakers of different tenants in the logs
+	logAttrs []any
+
+	mu                  sync.Mutex
+	state               domain.BreakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpenInFlight    int
+	halfOpenSuccesses   int
+	trips               int64
+	rejected            int64
+}
+
+// NewCircuitBreaker wraps sender with a breaker that opens after failureThreshold
+// consecutive failures, rejects calls for openTimeout and then lets up to
+// halfOpenMax probe calls through before closing again.
+func NewCircuitBreaker(sender ports.MessageSender, failureThreshold int,
+	openTimeout time.Duration, halfOpenMax int) ports.CircuitBreaker {
+	return newCircuitBreaker(sender, failureThreshold, openTimeout, halfOpenMax)
+}
+
+func newCircuitBreaker(sender ports.MessageSender, failureThreshold int,
+	openTimeout time.Duration, halfOpenMax int, logAttrs ...any) *circuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = 5
+	}
+	if openTimeout <= 0 {
+		openTimeout = 30 * time.Second
+	}
+	if halfOpenMax <= 0 {
+		halfOpenMax = 1
+	}
+
+	return &circuitBreaker{
+		next:             sender,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		halfOpenMax:      halfOpenMax,
+		logAttrs:         logAttrs,
+		state:            domain.BreakerClosed,
+	}
+}
+
+func (b *circuitBreaker) Send(ctx context.Context, message domain.Message) (string, error) {
+	if err := b.acquire(); err != nil {
+		return "", err
+	}
+
+	messageID, err := b.next.Send(ctx, message)
+
+	// A cancelled caller or a send held back by the rate limiter says nothing about the
+	// health of the provider
+	var deferred *domain.DeferredError
+	if err != nil && (ctx.Err() != nil && errors.Is(err, ctx.Err()) || errors.As(err, &deferred)) {
+		b.release()
+		return messageID, err
+	}
+
+	b.record(!providerFailure(err))
+	return messageID, err
+}
+
+// providerFailure reports whether err says the provider is unhealthy: a 5xx, a network
+// error or a timeout. A 4xx or a message rejected before sending is about that message only,
+// and the provider answering at all shows it is up.
+func providerFailure(err error) bool {
+	var provider *domain.ProviderError
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &provider):
+		return provider.StatusCode >= 500
+	case errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &netErr):
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *circuitBreaker) Status() domain.BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := domain.BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Trips:               b.trips,
+		Rejected:            b.rejected,
+	}
+
+	if b.state != domain.BreakerClosed {
+		openedAt := b.openedAt
+		retryAt := b.openedAt.Add(b.openTimeout)
+		status.OpenedAt = &openedAt
+		status.RetryAt = &retryAt
+	}
+
+	return status
+}
+
+// acquire decides whether a call may go through, moving an expired open breaker to half-open
+func (b *circuitBreaker) acquire() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == domain.BreakerOpen && time.Since(b.openedAt) >= b.openTimeout {
+		b.state = domain.BreakerHalfOpen
+		b.halfOpenInFlight = 0
+		b.halfOpenSuccesses = 0
+		slog.Info("Circuit breaker half-open, probing message sender", b.logAttrs...)
+	}
+
+	switch b.state {
+	case domain.BreakerOpen:
+		b.rejected++
+		return domain.ErrCircuitOpen
+	case domain.BreakerHalfOpen:
+		if b.halfOpenInFlight+b.halfOpenSuccesses >= b.halfOpenMax {
+			b.rejected++
+			return domain.ErrCircuitOpen
+		}
+		b.halfOpenInFlight++
+	}
+
+	return nil
+}
+
+// release gives back a half-open slot without recording an outcome
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == domain.BreakerHalfOpen && b.halfOpenInFlight > 0 {
+		b.halfOpenInFlight--
+	}
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == domain.BreakerHalfOpen {
+		if b.halfOpenInFlight > 0 {
+			b.halfOpenInFlight--
+		}
+		if !success {
+			b.consecutiveFailures++
+			b.trip()
+			return
+		}
+		b.halfOpenSuccesses++
+		if b.halfOpenSuccesses >= b.halfOpenMax {
+			b.state = domain.BreakerClosed
+			b.consecutiveFailures = 0
+			slog.Info("Circuit breaker closed, message sender recovered", b.logAttrs...)
+		}
+		return
+	}
+
+	if success {
+		b.consecutiveFailures = 0
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.state == domain.BreakerClosed && b.consecutiveFailures >= b.failureThreshold {
+		b.trip()
+	}
+}
+
+func (b *circuitBreaker) trip() {
+	b.state = domain.BreakerOpen
+	b.openedAt = time.Now()
+	b.trips++
+	slog.Warn("Circuit breaker opened", append([]any{"consecutive_failures", b.consecutiveFailures}, b.logAttrs...)...)
+}
+
+type tenantCircuitBreaker struct {
+	newBreaker func(tenantID string) *circuitBreaker
+	shared     *circuitBreaker
+
+	mu      sync.Mutex
+	tenants map[string]*circuitBreaker
+}
+
+// NewTenantCircuitBreaker gives every tenant a breaker of its own with the NewCircuitBreaker
+// settings, since each tenant may have its own webhook: one tenant's broken endpoint must
+// not hold up the others. Messages without a tenant share one breaker, whose state is the
+// top level of Status.
+func NewTenantCircuitBreaker(sender ports.MessageSender, failureThreshold int,
+	openTimeout time.Duration, halfOpenMax int) ports.CircuitBreaker {
+	return &tenantCircuitBreaker{
+		newBreaker: func(tenantID string) *circuitBreaker {
+			return newCircuitBreaker(sender, failureThreshold, openTimeout, halfOpenMax, "tenant", tenantID)
+		},
+		shared:  newCircuitBreaker(sender, failureThreshold, openTimeout, halfOpenMax),
+		tenants: make(map[string]*circuitBreaker),
+	}
+}
+
+func (t *tenantCircuitBreaker) Send(ctx context.Context, message domain.Message) (string, error) {
+	return t.breaker(message.TenantID).Send(ctx, message)
+}
+
+func (t *tenantCircuitBreaker) Status() domain.BreakerStatus {
+	status := t.shared.Status()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.tenants) > 0 {
+		status.Tenants = make(map[string]domain.BreakerStatus, len(t.tenants))
+		for tenantID, breaker := range t.tenants {
+			status.Tenants[tenantID] = breaker.Status()
+		}
+	}
+	return status
+}
+
+// breaker returns the breaker of tenantID, creating it on its first message
+func (t *tenantCircuitBreaker) breaker(tenantID string) *circuitBreaker {
+	if tenantID == "" {
+		return t.shared
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	breaker, ok := t.tenants[tenantID]
+	if !ok {
+		breaker = t.newBreaker(tenantID)
+		t.tenants[tenantID] = breaker
+	}
+	return breaker
+}
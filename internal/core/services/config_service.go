@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+// reloadableSettings are read afresh wherever they are used, so a reload can swap them in.
+// Everything else is wired into clients and services at startup.
+var reloadableSettings = []struct {
+	name string
+	// apply copies the setting from src into dst, reporting whether it changed
+	apply func(dst *config.Config, src config.Config) bool
+}{
+	{"app.send_interval_seconds", func(dst *config.Config, src config.Config) bool {
+		return swapSetting(&dst.App.SendIntervalSecs, src.App.SendIntervalSecs)
+	}},
+	{"app.batch_size", func(dst *config.Config, src config.Config) bool {
+		return swapSetting(&dst.App.BatchSize, src.App.BatchSize)
+	}},
+	{"app.max_retries", func(dst *config.Config, src config.Config) bool {
+		return swapSetting(&dst.App.MaxRetries, src.App.MaxRetries)
+	}},
+	{"logging.level", func(dst *config.Config, src config.Config) bool {
+		return swapSetting(&dst.Logging.Level, src.Logging.Level)
+	}},
+	// Tenants are looked up per message and per request, so teams can be added or changed live
+	{"tenants", func(dst *config.Config, src config.Config) bool {
+		if reflect.DeepEqual(dst.Tenants, src.Tenants) {
+			return false
+		}
+		dst.Tenants = src.Tenants
+		return true
+	}},
+}
+
+type configService struct {
+	current atomic.Pointer[config.Config]
+	load    func() (config.Config, error)
+	// reloads serializes reloads so two can't interleave their read and swap
+	reloads sync.Mutex
+}
+
+// NewConfigService serves initial, which should already be validated, until a reload
+// swaps in new values read by load
+func NewConfigService(initial config.Config, load func() (config.Config, error)) ports.ConfigService {
+	s := &configService{load: load}
+	s.current.Store(&initial)
+	return s
+}
+
+func (s *configService) Current() config.Config {
+	return *s.current.Load()
+}
+
+func (s *configService) Reload(ctx context.Context) (domain.ConfigReload, error) {
+	s.reloads.Lock()
+	defer s.reloads.Unlock()
+
+	loaded, err := s.load()
+	if err != nil {
+		return domain.ConfigReload{}, err
+	}
+	if err := loaded.Validate(); err != nil {
+		return domain.ConfigReload{}, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+	}
+
+	next := s.Current()
+	result := domain.ConfigReload{Applied: []string{}, RestartRequired: []string{}}
+	for _, setting := range reloadableSettings {
+		if setting.apply(&next, loaded) {
+			result.Applied = append(result.Applied, setting.name)
+		}
+	}
+	// With the reloadable settings copied over, any remaining difference needs a restart
+	result.RestartRequired = changedSections(next, loaded)
+
+	if len(result.Applied) > 0 {
+		if err := logging.SetLevel(next.Logging.Level); err != nil {
+			return domain.ConfigReload{}, fmt.Errorf("%w: %v", domain.ErrInvalidConfig, err)
+		}
+		s.current.Store(&next)
+	}
+
+	slog.InfoContext(ctx, "Config reloaded", "applied", result.Applied, "restart_required", result.RestartRequired)
+	return result, nil
+}
+
+// changedSections names the top-level config sections that differ, without their values
+func changedSections(a, b config.Config) []string {
+	sections := []string{}
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := 0; i < av.NumField(); i++ {
+		if !reflect.DeepEqual(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			name, _, _ := strings.Cut(av.Type().Field(i).Tag.Get("mapstructure"), ",")
+			sections = append(sections, name)
+		}
+	}
+	return sections
+}
+
+func swapSetting[T comparable](dst *T, value T) bool {
+	if *dst == value {
+		return false
+	}
+	*dst = value
+	return true
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+)
+
+type DedupeMode string
+
+const (
+	// DedupeReject refuses an identical message at enqueue
+	DedupeReject DedupeMode = "reject"
+	// DedupeCollapse accepts it but marks it duplicate instead of sending it
+	DedupeCollapse DedupeMode = "collapse"
+
+	dedupeKeyPrefix = "dedupe:"
+	// dedupeClaiming holds the key while the first message is being stored and has no ID yet
+	dedupeClaiming = "claiming"
+)
+
+// dedupeHash identifies what the handset would receive; the recipient is already E.164.
+// Tenants are deduped separately so one team's message never swallows another's.
+func dedupeHash(tenantID, to, content string) string {
+	input := to + "\x00" + content
+	if tenantID != "" {
+		input = tenantID + "\x00" + input
+	}
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+// rejectDuplicate claims the dedupe key for msg at enqueue. It returns a DuplicateError
+// if an identical message holds it, and a release func to call if msg isn't stored after all.
+// Cache failures let the message through: a duplicate text is better than a lost one.
+func (s *messageService) rejectDuplicate(ctx context.Context, msg *domain.Message) (func(), error) {
+	noop := func() {}
+	key := dedupeKeyPrefix + msg.DedupeHash
+
+	claimed, err := s.cache.SetIfAbsent(ctx, key, dedupeClaiming, s.dedupeWindow)
+	if err != nil {
+		slog.WarnContext(ctx, "Dedupe check failed, accepting message", "recipient", logging.MaskPhone(msg.To), "error", err)
+		return noop, nil
+	}
+	if !claimed {
+		return noop, &domain.DuplicateError{OriginalID: s.dedupeOwner(ctx, key)}
+	}
+
+	return func() {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			slog.WarnContext(ctx, "Failed to release dedupe key", "recipient", logging.MaskPhone(msg.To), "error", err)
+		}
+	}, nil
+}
+
+// recordDedupeOwner points the claimed key at the stored message
+func (s *messageService) recordDedupeOwner(ctx context.Context, msg *domain.Message) {
+	key := dedupeKeyPrefix + msg.DedupeHash
+	if err := s.cache.SetWithTTL(ctx, key, strconv.FormatUint(uint64(msg.ID), 10), s.dedupeWindow); err != nil {
+		logging.ForMessage(*msg).WarnContext(ctx, "Failed to record dedupe owner", "error", err)
+	}
+}
+
+// duplicateOf claims the dedupe key for msg at dispatch and returns the ID of the identical
+// message that already holds it, or 0 if msg may be sent. Retries of msg find their own ID.
+func (s *messageService) duplicateOf(ctx context.Context, msg domain.Message) uint {
+	hash := msg.DedupeHash
+	if hash == "" {
+		hash = dedupeHash(msg.TenantID, msg.To, msg.Content)
+	}
+	key := dedupeKeyPrefix + hash
+
+	claimed, err := s.cache.SetIfAbsent(ctx, key, strconv.FormatUint(uint64(msg.ID), 10), s.dedupeWindow)
+	if err != nil {
+		logging.ForMessage(msg).WarnContext(ctx, "Dedupe check failed, sending message", "error", err)
+		return 0
+	}
+	if claimed {
+		return 0
+	}
+
+	owner := s.dedupeOwner(ctx, key)
+	if owner == msg.ID {
+		return 0
+	}
+	return owner
+}
+
+// dedupeOwner reads the message ID holding key, 0 if unknown
+func (s *messageService) dedupeOwner(ctx context.Context, key string) uint {
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCacheMiss) {
+			slog.WarnContext(ctx, "Failed to read dedupe key", "error", err)
+		}
+		return 0
+	}
+
+	id, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
+
+func (s *messageService) markDuplicate(ctx context.Context, msg domain.Message, originalID uint) {
+	from := msg.Status
+	if err := msg.MarkDuplicate(originalID, time.Now()); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to mark message as duplicate", "error", err)
+		return
+	}
+	if err := s.repo.UpdateMessageStatus(ctx, msg, from); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to mark message as duplicate", "error", err)
+		return
+	}
+	s.notify(ctx, msg)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+const (
+	healthCheckTimeout = 2 * time.Second
+	// A running sender that hasn't finished a tick in this many intervals is stalled
+	senderStallIntervals = 3
+	minSenderStall       = 30 * time.Second
+)
+
+type healthService struct {
+	liveness  []ports.HealthCheck
+	readiness []ports.HealthCheck
+}
+
+// NewHealthService creates the probe service. Liveness only runs the liveness checks, so a
+// database outage doesn't get the process restarted; readiness runs both sets.
+func NewHealthService(liveness, dependencies []ports.HealthCheck) ports.HealthService {
+	return &healthService{
+		liveness:  liveness,
+		readiness: append(append([]ports.HealthCheck{}, liveness...), dependencies...),
+	}
+}
+
+func (s *healthService) Liveness(ctx context.Context) domain.HealthReport {
+	return runChecks(ctx, s.liveness)
+}
+
+func (s *healthService) Readiness(ctx context.Context) domain.HealthReport {
+	return runChecks(ctx, s.readiness)
+}
+
+// runChecks runs the checks concurrently, each under its own timeout
+func runChecks(ctx context.Context, checks []ports.HealthCheck) domain.HealthReport {
+	report := domain.HealthReport{Status: domain.HealthUp, Checks: make(map[string]domain.CheckResult, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check ports.HealthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(checkCtx)
+			result := domain.CheckResult{Status: domain.HealthUp, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = domain.HealthDown
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name()] = result
+			if err != nil {
+				report.Status = domain.HealthDown
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+type senderHealthCheck struct {
+	sender ports.SenderMonitor
+}
+
+// NewSenderHealthCheck fails while the auto-sender is running but hasn't completed a
+// tick for several intervals, i.e. its goroutine died or a tick is stuck. A sender
+// stopped through the API is healthy.
+func NewSenderHealthCheck(sender ports.SenderMonitor) ports.HealthCheck {
+	return &senderHealthCheck{sender: sender}
+}
+
+func (c *senderHealthCheck) Name() string {
+	return "auto_sender"
+}
+
+func (c *senderHealthCheck) Check(ctx context.Context) error {
+	status := c.sender.SenderStatus()
+	// Until the first tick, the start counts as one so a fresh sender isn't reported stalled
+	lastTick := status.LastTickAt
+	if lastTick == nil {
+		lastTick = status.StartedAt
+	}
+	if !status.Running || lastTick == nil {
+		return nil
+	}
+
+	stall := time.Duration(status.IntervalSeconds) * time.Second * senderStallIntervals
+	if stall < minSenderStall {
+		stall = minSenderStall
+	}
+	if since := time.Since(*lastTick); since > stall {
+		return fmt.Errorf("no tick completed for %s", since.Round(time.Second))
+	}
+	return nil
+}
@@ -2,35 +2,181 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/hasElvin/messenger-svc/config"
-	"log"
+	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+	"github.com/hasElvin/messenger-svc/internal/core/netguard"
+	"github.com/hasElvin/messenger-svc/internal/core/phone"
 	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"github.com/hasElvin/messenger-svc/internal/core/sms"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// defaultBatchSize is how many pending messages a tick picks up when none is configured
+const defaultBatchSize = 2
+
 type messageService struct {
-	repo      ports.MessageRepository
-	cache     ports.CacheService
-	sender    ports.MessageSender
-	stopChan  chan struct{}
-	isRunning bool
-	mu        sync.RWMutex
+	repo          ports.MessageRepository
+	cache         ports.CacheService
+	sender        ports.MessageSender
+	notifier      ports.StatusNotifier
+	callbacks     bool
+	metrics       ports.DispatchMetrics
+	config        ports.ConfigProvider
+	charLimit     int
+	maxSegments   int
+	multipartMode MultipartMode
+	defaultRegion string
+	templates     ports.TemplateRenderer
+	recipients    ports.RecipientRepository
+	suppressions  ports.SuppressionChecker
+	quietHours    map[string]domain.QuietWindow
+	dedupeMode    DedupeMode
+	dedupeWindow  time.Duration
+	stopChan      chan struct{}
+	// cancelSender ends the running sender's context, so a stop or drain also cuts short
+	// the batch under way whatever context the sender was started with
+	cancelSender context.CancelFunc
+	isRunning    bool
+	// intervalOverride and batchOverride are set when the sender is started with explicit
+	// values; zero follows the configuration, including reloads
+	intervalOverride int
+	batchOverride    int
+	startedAt        time.Time
+	lastTickAt       time.Time
+	lastBatchSize    int
+	nextTickAt       time.Time
+	counters         senderCounters
+	senders          sync.WaitGroup
+	mu               sync.RWMutex
+}
+
+// MessageServiceOption plugs an optional collaborator into the message service
+type MessageServiceOption func(*messageService)
+
+// WithStatusNotifier reports every persisted status change to notifier. Messages may only
+// carry a callback_url when a notifier is plugged in.
+func WithStatusNotifier(notifier ports.StatusNotifier) MessageServiceOption {
+	return func(s *messageService) {
+		s.notifier = notifier
+		s.callbacks = true
+	}
+}
+
+// WithContentLimits rejects messages over charLimit characters or maxSegments SMS segments at
+// enqueue. The character limit doesn't apply with WithMultipart.
+func WithContentLimits(charLimit, maxSegments int) MessageServiceOption {
+	return func(s *messageService) {
+		s.charLimit = charLimit
+		s.maxSegments = maxSegments
+	}
+}
+
+// WithMultipart splits content needing more than one segment into parts sent in order
+func WithMultipart(mode MultipartMode) MessageServiceOption {
+	return func(s *messageService) {
+		s.multipartMode = mode
+	}
+}
+
+// WithDefaultRegion reads recipients written without a country code, like 0555 111 22 33,
+// as national numbers of region (ISO 3166-1 alpha-2)
+func WithDefaultRegion(region string) MessageServiceOption {
+	return func(s *messageService) {
+		s.defaultRegion = region
+	}
+}
+
+// WithTemplates lets messages reference a template_id whose body is rendered at enqueue
+func WithTemplates(templates ports.TemplateRenderer) MessageServiceOption {
+	return func(s *messageService) {
+		s.templates = templates
+	}
+}
+
+// WithRecipients picks the template locale from the recipient's stored preference
+// when a message doesn't ask for one
+func WithRecipients(recipients ports.RecipientRepository) MessageServiceOption {
+	return func(s *messageService) {
+		s.recipients = recipients
+	}
+}
+
+// WithSuppressions marks messages to opted-out recipients suppressed instead of sending them
+func WithSuppressions(suppressions ports.SuppressionChecker) MessageServiceOption {
+	return func(s *messageService) {
+		s.suppressions = suppressions
+	}
+}
+
+// WithQuietHours defers messages whose category has a window while it is quiet
+// hours in the recipient's timezone; categories not in windows are never held back
+func WithQuietHours(windows map[string]domain.QuietWindow) MessageServiceOption {
+	return func(s *messageService) {
+		s.quietHours = windows
+	}
+}
+
+// WithDedupe treats messages with the same recipient and content within window as
+// duplicates, rejecting them at enqueue or collapsing them at dispatch depending on mode
+func WithDedupe(mode DedupeMode, window time.Duration) MessageServiceOption {
+	return func(s *messageService) {
+		s.dedupeMode = mode
+		s.dedupeWindow = window
+	}
+}
+
+// WithConfig reads the send interval, batch size, retry limit and tenant overrides from
+// provider on every tick, so reloaded values apply without restarting the sender
+func WithConfig(provider ports.ConfigProvider) MessageServiceOption {
+	return func(s *messageService) {
+		s.config = provider
+	}
+}
+
+// WithMetrics records dispatch outcomes, tick durations and the auto-sender state
+func WithMetrics(metrics ports.DispatchMetrics) MessageServiceOption {
+	return func(s *messageService) {
+		s.metrics = metrics
+	}
 }
 
 func NewMessageService(repo ports.MessageRepository, cache ports.CacheService,
-	sender ports.MessageSender) ports.MessageService {
-	return &messageService{
-		repo:   repo,
-		cache:  cache,
-		sender: sender,
+	sender ports.MessageSender, opts ...MessageServiceOption) ports.MessageService {
+	s := &messageService{
+		repo:     repo,
+		cache:    cache,
+		sender:   sender,
+		notifier: noopNotifier{},
+		metrics:  noopMetrics{},
+		config:   staticConfig{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
 	}
+
+	return s
 }
 
-func (s *messageService) StartAutoSender(ctx context.Context, intervalSeconds int) error {
+// StartAutoSender sends up to batchSize pending messages every intervalSeconds. Either
+// left at zero follows the configured value.
+func (s *messageService) StartAutoSender(ctx context.Context, intervalSeconds, batchSize int) error {
+	if intervalSeconds < 0 || batchSize < 0 {
+		return fmt.Errorf("send interval and batch size can't be negative")
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -38,11 +184,30 @@ func (s *messageService) StartAutoSender(ctx context.Context, intervalSeconds in
 		return fmt.Errorf("auto sender is already running")
 	}
 
+	s.intervalOverride = intervalSeconds
+	s.batchOverride = batchSize
+	interval, batch := s.senderSettings(s.config.Current())
+	if interval <= 0 {
+		return fmt.Errorf("send interval must be at least 1 second")
+	}
+
+	now := time.Now()
+	ctx, s.cancelSender = context.WithCancel(ctx)
 	s.stopChan = make(chan struct{})
 	s.isRunning = true
+	s.startedAt = now
+	s.lastTickAt = time.Time{}
+	s.lastBatchSize = 0
+	s.nextTickAt = now.Add(interval)
+	s.counters.reset()
+	s.metrics.SetSenderRunning(true)
 
-	go s.runAutoSender(ctx, intervalSeconds)
-	log.Println("Auto sender started")
+	s.senders.Add(1)
+	go func(stop chan struct{}) {
+		defer s.senders.Done()
+		s.runAutoSender(ctx, interval, stop)
+	}(s.stopChan)
+	slog.InfoContext(ctx, "Auto sender started", "interval", interval, "batch_size", batch)
 
 	return nil
 }
@@ -56,77 +221,807 @@ func (s *messageService) StopAutoSender(ctx context.Context) error {
 	}
 
 	close(s.stopChan)
+	s.cancelSender()
 	s.isRunning = false
-	log.Println("Auto sender stopped")
+	s.metrics.SetSenderRunning(false)
+	slog.InfoContext(ctx, "Auto sender stopped")
 
 	return nil
 }
 
-func (s *messageService) GetSentMessages(ctx context.Context) ([]domain.Message, error) {
-	return s.repo.GetSentMessages(ctx)
+// Drain stops the auto-sender and waits until it has exited, which it does once the message
+// being sent has been recorded. It gives up when ctx ends.
+func (s *messageService) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	if s.isRunning {
+		close(s.stopChan)
+		s.cancelSender()
+		s.isRunning = false
+		s.metrics.SetSenderRunning(false)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.senders.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-func (s *messageService) runAutoSender(ctx context.Context, intervalSeconds int) {
-	ticker := time.NewTicker(time.Duration(intervalSeconds) * time.Second)
-	defer ticker.Stop()
+func (s *messageService) SenderStatus() domain.SenderStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := domain.SenderStatus{
+		Running:       s.isRunning,
+		LastBatchSize: s.lastBatchSize,
+		Counters:      s.counters.snapshot(),
+	}
+	if !s.lastTickAt.IsZero() {
+		lastTickAt := s.lastTickAt
+		status.LastTickAt = &lastTickAt
+	}
+	if s.isRunning {
+		startedAt, nextTickAt := s.startedAt, s.nextTickAt
+		interval, batch := s.senderSettings(s.config.Current())
+		status.IntervalSeconds = int(interval / time.Second)
+		status.BatchSize = batch
+		status.StartedAt = &startedAt
+		status.NextTickAt = &nextTickAt
+	}
+	return status
+}
+
+func (s *messageService) EnqueueMessage(ctx context.Context, msg *domain.Message) error {
+	if strings.TrimSpace(msg.To) == "" {
+		return fmt.Errorf("%w: recipient is required", domain.ErrInvalidMessage)
+	}
+	charLimit, err := s.charLimitFor(msg.TenantID)
+	if err != nil {
+		return err
+	}
+	recipient, err := phone.Parse(msg.To, s.defaultRegion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidMessage, err)
+	}
+	msg.To = recipient.E164
+	msg.CountryCode = recipient.Region
+	msg.Category = strings.ToLower(strings.TrimSpace(msg.Category))
+	if err := s.resolvePreferences(ctx, msg, recipient); err != nil {
+		return err
+	}
+	if err := s.renderTemplate(ctx, msg); err != nil {
+		return err
+	}
+	if strings.TrimSpace(msg.Content) == "" {
+		return fmt.Errorf("%w: content is required", domain.ErrInvalidMessage)
+	}
+	if msg.CallbackURL != "" {
+		if !s.callbacks {
+			return fmt.Errorf("%w: status callbacks are not enabled", domain.ErrInvalidMessage)
+		}
+		if err := netguard.CheckURL(msg.CallbackURL); err != nil {
+			return fmt.Errorf("%w: callback_url %v", domain.ErrInvalidMessage, err)
+		}
+	}
+
+	// Transliteration runs before segmentation so limits and parts reflect what is actually sent
+	if msg.Transliterate {
+		msg.Content = sms.Transliterate(msg.Content)
+	}
+
+	info, err := checkContent(msg.Content, s.contentCharLimit(charLimit), s.maxSegments)
+	if err != nil {
+		return err
+	}
+	msg.Encoding = string(info.Encoding)
+	msg.Segments = info.Segments
+
+	if s.multipartMode != "" {
+		splitMessage(msg, s.multipartMode)
+		if s.maxSegments > 0 && len(msg.Parts) > s.maxSegments {
+			return fmt.Errorf("%w: content needs %d parts, limit is %d",
+				domain.ErrInvalidMessage, len(msg.Parts), s.maxSegments)
+		}
+	}
+
+	msg.Status = domain.StatusPending
+	msg.TraceParent = traceParent(ctx)
+	if s.dedupeMode == "" {
+		return s.repo.CreateMessage(ctx, msg)
+	}
+
+	msg.DedupeHash = dedupeHash(msg.TenantID, msg.To, msg.Content)
+	if s.dedupeMode != DedupeReject {
+		return s.repo.CreateMessage(ctx, msg)
+	}
+
+	release, err := s.rejectDuplicate(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateMessage(ctx, msg); err != nil {
+		release()
+		return err
+	}
+	s.recordDedupeOwner(ctx, msg)
+	return nil
+}
+
+// charLimitFor returns the character limit of tenantID, rejecting tenants that aren't configured
+func (s *messageService) charLimitFor(tenantID string) (int, error) {
+	if tenantID == "" {
+		return s.charLimit, nil
+	}
+	tenant, ok := s.config.Current().Tenants[tenantID]
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown tenant %q", domain.ErrInvalidMessage, tenantID)
+	}
+	if tenant.MessageCharLimit > 0 {
+		return tenant.MessageCharLimit, nil
+	}
+	return s.charLimit, nil
+}
+
+// renderTemplate fills msg.Content from the referenced template, pinning the version used
+func (s *messageService) renderTemplate(ctx context.Context, msg *domain.Message) error {
+	if msg.TemplateID == 0 {
+		return nil
+	}
+	if s.templates == nil {
+		return fmt.Errorf("%w: templates are not enabled", domain.ErrInvalidMessage)
+	}
+	if msg.Content != "" {
+		return fmt.Errorf("%w: content and template_id are mutually exclusive", domain.ErrInvalidMessage)
+	}
+
+	rendered, err := s.templates.Render(ctx, msg.TemplateID, msg.TemplateVersion, msg.Locale, msg.Variables)
+	if errors.Is(err, domain.ErrTemplateNotFound) {
+		return fmt.Errorf("%w: %w", domain.ErrInvalidMessage, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	msg.Content = rendered.Content
+	msg.TemplateVersion = rendered.Version
+	msg.Locale = rendered.Locale
+	return nil
+}
+
+// resolvePreferences validates the requested locale and timezone and fills whichever is
+// missing from the recipient's stored preferences. The timezone finally falls back to
+// the one of the number's country.
+func (s *messageService) resolvePreferences(ctx context.Context, msg *domain.Message, number phone.Number) error {
+	if msg.Locale != "" {
+		locale := domain.NormalizeLocale(msg.Locale)
+		if locale == "" {
+			return fmt.Errorf("%w: locale %q is not a valid language tag", domain.ErrInvalidMessage, msg.Locale)
+		}
+		msg.Locale = locale
+	}
+	if msg.Timezone != "" {
+		if _, err := time.LoadLocation(msg.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidMessage, msg.Timezone)
+		}
+	}
+
+	if (msg.Locale == "" || msg.Timezone == "") && s.recipients != nil {
+		recipient, err := s.recipients.GetRecipient(ctx, msg.To)
+		switch {
+		case errors.Is(err, domain.ErrRecipientNotFound):
+		case err != nil:
+			return err
+		default:
+			if msg.Locale == "" {
+				msg.Locale = recipient.Locale
+			}
+			if msg.Timezone == "" {
+				msg.Timezone = recipient.Timezone
+			}
+		}
+	}
+
+	if msg.Timezone == "" {
+		msg.Timezone = number.Timezone
+	}
+	return nil
+}
+
+func (s *messageService) PreviewTransliteration(content string) domain.TransliterationPreview {
+	original := summarize(content)
+	transliterated := summarize(sms.Transliterate(content))
+
+	return domain.TransliterationPreview{
+		Original:       original,
+		Transliterated: transliterated,
+		SegmentsSaved:  original.Segments - transliterated.Segments,
+	}
+}
+
+func summarize(content string) domain.EncodingSummary {
+	info := sms.Analyze(content)
+	return domain.EncodingSummary{
+		Content:  content,
+		Encoding: string(info.Encoding),
+		Units:    info.Units,
+		Segments: info.Segments,
+	}
+}
+
+// contentCharLimit is the character limit content is held to. Long content is split rather
+// than rejected when multipart is on, so only the segment limit caps it then.
+func (s *messageService) contentCharLimit(charLimit int) int {
+	if s.multipartMode != "" {
+		return 0
+	}
+	return charLimit
+}
+
+// checkContent works out how content will be encoded and rejects it if it is over
+// charLimit characters or maxSegments segments; a zero limit is not enforced
+func checkContent(content string, charLimit, maxSegments int) (sms.Info, error) {
+	info := sms.Analyze(content)
+
+	if chars := utf8.RuneCountInString(content); charLimit > 0 && chars > charLimit {
+		return info, fmt.Errorf("%w: content is %d characters, limit is %d",
+			domain.ErrInvalidMessage, chars, charLimit)
+	}
+	if maxSegments > 0 && info.Segments > maxSegments {
+		return info, fmt.Errorf("%w: content needs %d %s segments, limit is %d",
+			domain.ErrInvalidMessage, info.Segments, info.Encoding, maxSegments)
+	}
+
+	return info, nil
+}
+
+func (s *messageService) GetSentMessages(ctx context.Context, tenantID string) ([]domain.Message, error) {
+	return s.repo.GetSentMessages(ctx, tenantID)
+}
+
+// CancelMessage withdraws a message the sender hasn't sent yet, along with its unsent parts.
+// The update is conditional on the message still being pending, so a message the sender
+// got to first is reported as a conflict rather than cancelled.
+func (s *messageService) CancelMessage(ctx context.Context, id uint, tenantID string) (*domain.Message, error) {
+	msg, err := s.repo.GetMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Another tenant's message is reported missing so IDs can't be probed across tenants
+	if tenantID != "" && msg.TenantID != tenantID {
+		return nil, domain.ErrMessageNotFound
+	}
+
+	now := time.Now()
+	from := msg.Status
+	if err := msg.MarkCancelled(now); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateMessageStatus(ctx, *msg, from); err != nil {
+		return nil, err
+	}
 
-	cfg := config.LoadConfig()
+	// Parts already sent can't be called back; the rest are never picked up once the
+	// message is cancelled, but are marked so the record is accurate
+	for i, part := range msg.Parts {
+		if part.Status != domain.StatusPending {
+			continue
+		}
+		if err := part.MarkCancelled(now); err != nil {
+			return nil, err
+		}
+		if err := s.repo.UpdateMessageStatus(ctx, part, domain.StatusPending); err != nil {
+			logging.ForMessage(part).WarnContext(ctx, "Failed to mark part as cancelled", "error", err)
+			continue
+		}
+		msg.Parts[i] = part
+	}
+	s.notify(ctx, *msg)
+
+	logging.ForMessage(*msg).InfoContext(ctx, "Message cancelled")
+	return msg, nil
+}
+
+// senderSettings returns the send interval and batch size in effect: the ones the sender
+// was started with, otherwise the configured ones. Callers hold s.mu.
+func (s *messageService) senderSettings(cfg config.Config) (time.Duration, int) {
+	intervalSeconds, batchSize := s.intervalOverride, s.batchOverride
+	if intervalSeconds == 0 {
+		intervalSeconds = cfg.App.SendIntervalSecs
+	}
+	if batchSize == 0 {
+		batchSize = cfg.App.BatchSize
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return time.Duration(intervalSeconds) * time.Second, batchSize
+}
+
+func (s *messageService) runAutoSender(ctx context.Context, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case tick := <-ticker.C:
+			cfg := s.config.Current()
+			s.mu.RLock()
+			_, cfg.App.BatchSize = s.senderSettings(cfg)
+			s.mu.RUnlock()
+
 			s.SendPendingMessages(ctx, &cfg)
-		case <-s.stopChan:
+
+			now := time.Now()
+			s.mu.Lock()
+			s.lastTickAt = now
+			// Pick up a reloaded interval from the next tick on
+			if next, _ := s.senderSettings(s.config.Current()); next > 0 && next != interval {
+				slog.InfoContext(ctx, "Send interval changed", "from", interval, "to", next)
+				interval = next
+				ticker.Reset(interval)
+				tick = now
+			}
+			// A tick that overran its interval is followed by one straight away
+			s.nextTickAt = tick.Add(interval)
+			if s.nextTickAt.Before(now) {
+				s.nextTickAt = now
+			}
+			s.mu.Unlock()
+		case <-stop:
 			return
 		case <-ctx.Done():
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			s.mu.Lock()
+			// Unless it was stopped and restarted meanwhile, the sender is no longer running
+			if s.stopChan == stop {
+				s.isRunning = false
+				s.metrics.SetSenderRunning(false)
+			}
+			s.mu.Unlock()
+			slog.InfoContext(ctx, "Auto sender stopped, context canceled")
 			return
 		}
 	}
 }
 
 func (s *messageService) SendPendingMessages(ctx context.Context, cfg *config.Config) {
-	messages, err := s.repo.GetPendingMessages(ctx, 2, cfg.App.MessageCharLimit, cfg.App.MaxRetries)
+	defer func(start time.Time) { s.metrics.ObserveTick(time.Since(start)) }(time.Now())
+
+	ctx, span := tracer().Start(ctx, "messageService.SendPendingMessages")
+	defer span.End()
+
+	batchSize := cfg.App.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	messages, err := s.repo.GetPendingMessages(ctx, batchSize, cfg.MaxRetriesCeiling())
 	if err != nil {
-		log.Printf("Failed to fetch pending messages: %v", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "fetch pending messages")
+		slog.ErrorContext(ctx, "Failed to fetch pending messages", "error", err)
 		return
 	}
+	s.mu.Lock()
+	s.lastBatchSize = len(messages)
+	s.mu.Unlock()
 
+	// Each tick is its own trace; links lead back to the requests that enqueued its messages
+	span.SetAttributes(attribute.Int("messages.count", len(messages)))
 	for _, msg := range messages {
-		err := s.SendMessage(ctx, msg)
+		if link, ok := enqueueLink(msg); ok {
+			span.AddLink(link)
+		}
+	}
+
+	// Tenants whose circuit breaker rejected a send this tick
+	openBreakers := make(map[string]bool)
+	for i, msg := range messages {
+		if ctx.Err() != nil {
+			slog.InfoContext(ctx, "Sender is shutting down, leaving the rest for the next run", "skipped", len(messages)-i)
+			return
+		}
+		if openBreakers[msg.TenantID] {
+			continue
+		}
+		logger := logging.ForMessage(msg)
+		settings := cfg.TenantSettings(msg.TenantID)
+
+		// The query lets through as many retries as the most lenient tenant gets
+		if settings.MaxRetries > 0 && msg.RetryCount >= settings.MaxRetries {
+			err := fmt.Errorf("retry limit of %d reached", settings.MaxRetries)
+			logger.ErrorContext(ctx, "Marking message as failed, out of retries", "retries", msg.RetryCount)
+			s.metrics.MessageFailed(err)
+			s.counters.failed.Add(1)
+			s.markFailed(ctx, msg, err.Error())
+			continue
+		}
+
+		if s.suppressions != nil {
+			suppressed, err := s.suppressions.IsSuppressed(ctx, msg.To)
+			if err != nil {
+				// Without a definite answer it isn't safe to text the recipient; try next tick
+				logger.WarnContext(ctx, "Skipping message, suppression check failed", "error", err)
+				continue
+			}
+			if suppressed {
+				logger.InfoContext(ctx, "Message not sent, recipient opted out")
+				s.counters.suppressed.Add(1)
+				s.markSuppressed(ctx, msg)
+				continue
+			}
+		}
+
+		if opens, quiet := s.quietUntil(msg); quiet {
+			logger.InfoContext(ctx, "Message is in quiet hours, deferring", "category", msg.Category, "until", opens)
+			s.counters.deferred.Add(1)
+			if err := s.repo.DeferMessage(ctx, msg.ID, opens); err != nil {
+				logger.ErrorContext(ctx, "Failed to defer message", "error", err)
+			}
+			continue
+		}
+
+		if s.dedupeMode == DedupeCollapse {
+			if originalID := s.duplicateOf(ctx, msg); originalID != 0 {
+				logger.InfoContext(ctx, "Message duplicates an earlier one, not sending", "duplicate_of", originalID)
+				s.counters.duplicates.Add(1)
+				s.markDuplicate(ctx, msg, originalID)
+				continue
+			}
+		}
+
+		// Rows written straight to the database skip enqueue validation; fail them visibly
+		// rather than leaving them pending forever
+		if _, err := checkContent(msg.Content, s.contentCharLimit(settings.MessageCharLimit),
+			cfg.App.MaxSegments); err != nil {
+			logger.WarnContext(ctx, "Message can't be sent", "error", err)
+			s.metrics.MessageFailed(err)
+			s.counters.failed.Add(1)
+			s.markFailed(ctx, msg, err.Error())
+			continue
+		}
+
+		// A send that has started always runs to completion so its outcome is recorded;
+		// cancelling it halfway would leave the message pending after the provider got it
+		err := s.SendMessage(context.WithoutCancel(ctx), msg)
+		if errors.Is(err, domain.ErrCircuitOpen) {
+			// Nothing was dispatched, so this is not a retry; the tenant's other messages wait
+			// for a later tick while other tenants' webhooks carry on
+			logger.WarnContext(ctx, "Circuit breaker open, skipping the tenant's messages this tick",
+				"tenant", msg.TenantID)
+			openBreakers[msg.TenantID] = true
+			continue
+		}
+		if errors.Is(err, domain.ErrStatusConflict) {
+			// Another writer already moved the message on; it must not be retried
+			logger.WarnContext(ctx, "Message changed status during send", "error", err)
+			continue
+		}
+		var deferred *domain.DeferredError
+		if errors.As(err, &deferred) {
+			logger.InfoContext(ctx, "Deferring message", "reason", deferred.Reason, "retry_after", deferred.RetryAfter)
+			s.counters.deferred.Add(1)
+			if err := s.repo.DeferMessage(ctx, msg.ID, time.Now().Add(deferred.RetryAfter)); err != nil {
+				logger.ErrorContext(ctx, "Failed to defer message", "error", err)
+			}
+			continue
+		}
 		if err != nil {
-			log.Printf("Failed to send message ID %d: %v", msg.ID, err)
+			logger.WarnContext(ctx, "Failed to send message", "error", err)
 
 			msg.RetryCount++
-			if msg.RetryCount >= cfg.App.MaxRetries {
-				log.Printf("Marking message ID %d as failed after %d retries", msg.ID, msg.RetryCount)
+			if msg.RetryCount >= settings.MaxRetries {
+				logger.ErrorContext(ctx, "Marking message as failed, out of retries", "retries", msg.RetryCount)
+				s.metrics.MessageFailed(err)
+				s.counters.failed.Add(1)
 				_ = s.repo.IncrementRetryCount(ctx, msg.ID)
-				_ = s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusFailed)
+				s.markFailed(ctx, msg, err.Error())
 			} else {
+				s.metrics.MessageRetried(err)
+				s.counters.retried.Add(1)
 				_ = s.repo.IncrementRetryCount(ctx, msg.ID)
 			}
+			continue
 		}
+		s.metrics.MessageSent()
+		s.counters.sent.Add(1)
 	}
 }
 
 func (s *messageService) SendMessage(ctx context.Context, msg domain.Message) error {
+	opts := []trace.SpanStartOption{trace.WithAttributes(attribute.Int64("message.id", int64(msg.ID)),
+		attribute.Int("message.retry_count", msg.RetryCount))}
+	if link, ok := enqueueLink(msg); ok {
+		opts = append(opts, trace.WithLinks(link))
+	}
+	ctx, span := tracer().Start(ctx, "messageService.SendMessage", opts...)
+	defer span.End()
+
+	err := s.sendMessage(ctx, msg)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "send message")
+	}
+	return err
+}
+
+func (s *messageService) sendMessage(ctx context.Context, msg domain.Message) error {
+	if msg.IsMultipart() {
+		return s.sendParts(ctx, msg)
+	}
+
 	messageID, err := s.sender.Send(ctx, msg)
 	if err != nil {
 		return err
 	}
 
-	// Update message status
-	if err := s.repo.UpdateMessageStatus(ctx, msg.ID, domain.StatusSent); err != nil {
+	return s.markSent(ctx, msg, messageID)
+}
+
+// sendParts sends the remaining parts of a multipart message in order and marks the
+// logical message sent once all of them are out. Parts sent on an earlier attempt are
+// skipped, and a failure stops the later parts so the handset never gets them out of order.
+func (s *messageService) sendParts(ctx context.Context, msg domain.Message) error {
+	firstMessageID := ""
+	for _, part := range msg.Parts {
+		if part.Status != domain.StatusPending {
+			if firstMessageID == "" {
+				firstMessageID = part.ProviderMessageID
+			}
+			continue
+		}
+
+		messageID, err := s.sender.Send(ctx, part)
+		if err != nil {
+			return err
+		}
+
+		from := part.Status
+		if err := part.MarkSent(messageID, time.Now()); err != nil {
+			return err
+		}
+		if err := s.repo.UpdateMessageStatus(ctx, part, from); err != nil {
+			return fmt.Errorf("failed to update part %d/%d status: %w", part.PartNumber, part.PartTotal, err)
+		}
+		if firstMessageID == "" {
+			firstMessageID = messageID
+		}
+	}
+
+	return s.markSent(ctx, msg, firstMessageID)
+}
+
+func (s *messageService) markSent(ctx context.Context, msg domain.Message, messageID string) error {
+	// Update message status, keeping the provider ID to match delivery receipts against
+	from := msg.Status
+	sentAt := time.Now()
+	if err := msg.MarkSent(messageID, sentAt); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateMessageStatus(ctx, msg, from); err != nil {
 		return fmt.Errorf("failed to update message status: %w", err)
 	}
+	s.notify(ctx, msg)
 
 	// Cache the result
 	cacheKey := fmt.Sprintf("msg:%d", msg.ID)
-	cacheValue := fmt.Sprintf("messageId=%s|sentAt=%s", messageID, time.Now().Format(time.RFC3339))
+	cacheValue := fmt.Sprintf("messageId=%s|sentAt=%s", messageID, sentAt.Format(time.RFC3339))
 
 	if err := s.cache.Set(ctx, cacheKey, cacheValue); err != nil {
-		log.Printf("Failed to cache message %d: %v", msg.ID, err)
+		logging.ForMessage(msg).WarnContext(ctx, "Failed to cache message", "error", err)
 	}
 
-	log.Printf("Message %d sent successfully", msg.ID)
+	logging.ForMessage(msg).InfoContext(ctx, "Message sent", "provider_message_id", messageID)
 	return nil
 }
+
+func (s *messageService) ProcessDeliveryReceipt(ctx context.Context, receipt domain.DeliveryReceipt) error {
+	if receipt.Status != domain.StatusDelivered && receipt.Status != domain.StatusUndelivered {
+		return fmt.Errorf("unsupported delivery status %q", receipt.Status)
+	}
+
+	msg, err := s.repo.GetMessageByProviderID(ctx, receipt.ProviderMessageID)
+	if err != nil {
+		return err
+	}
+	// Receipts for later parts find the part; its status rolls up into the logical message
+	if msg.ParentID != nil {
+		if msg, err = s.repo.GetMessage(ctx, *msg.ParentID); err != nil {
+			return err
+		}
+	}
+
+	logger := logging.ForMessage(*msg).With("status", receipt.Status)
+	if receipt.ErrorCode != "" {
+		logger = logger.With("provider_error", receipt.ErrorCode)
+	}
+
+	status, reason := receipt.Status, receipt.ErrorCode
+	if msg.IsMultipart() {
+		part := slices.IndexFunc(msg.Parts, func(part domain.Message) bool {
+			return part.ProviderMessageID == receipt.ProviderMessageID
+		})
+		if part < 0 {
+			return fmt.Errorf("%w: no part of message %d has provider ID %s",
+				domain.ErrMessageNotFound, msg.ID, receipt.ProviderMessageID)
+		}
+		// Providers resend receipts until acknowledged, so a repeat is not an error
+		if msg.Parts[part].Status == receipt.Status {
+			logger.InfoContext(ctx, "Duplicate receipt ignored", "part", msg.Parts[part].PartNumber)
+			return nil
+		}
+		if err := s.recordDelivery(ctx, &msg.Parts[part], receipt.Status, receipt.ErrorCode); err != nil {
+			return err
+		}
+		logger = logger.With("part", msg.Parts[part].PartNumber)
+
+		// A receipt can beat the last parts out; the logical message only rolls up once sent
+		if status, reason = partsDelivery(msg.Parts); status == "" || msg.Status != domain.StatusSent {
+			logger.InfoContext(ctx, "Delivery receipt processed")
+			return nil
+		}
+	} else if msg.Status == receipt.Status {
+		logger.InfoContext(ctx, "Duplicate receipt ignored")
+		return nil
+	}
+
+	if err := s.recordDelivery(ctx, msg, status, reason); err != nil {
+		return err
+	}
+	s.notify(ctx, *msg)
+
+	logger.InfoContext(ctx, "Delivery receipt processed")
+	return nil
+}
+
+// recordDelivery moves a sent message or part to the delivered or undelivered status, keeping
+// the provider's reason for an undelivered one
+func (s *messageService) recordDelivery(ctx context.Context, msg *domain.Message, status domain.Status,
+	reason string) error {
+	from := msg.Status
+	var err error
+	if status == domain.StatusDelivered {
+		err = msg.MarkDelivered(time.Now())
+	} else {
+		err = msg.MarkUndelivered(reason, time.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateMessageStatus(ctx, *msg, from); err != nil {
+		return fmt.Errorf("failed to update message status: %w", err)
+	}
+	return nil
+}
+
+// partsDelivery derives a multipart message's delivery status from its parts: undelivered as
+// soon as one part is, with that part's reason, delivered once all are, and empty while
+// receipts are outstanding
+func partsDelivery(parts []domain.Message) (domain.Status, string) {
+	delivered := 0
+	for _, part := range parts {
+		switch part.Status {
+		case domain.StatusUndelivered:
+			reason := fmt.Sprintf("part %d/%d", part.PartNumber, part.PartTotal)
+			if part.FailureReason != "" {
+				reason += ": " + part.FailureReason
+			}
+			return domain.StatusUndelivered, reason
+		case domain.StatusDelivered:
+			delivered++
+		}
+	}
+	if delivered == len(parts) {
+		return domain.StatusDelivered, ""
+	}
+	return "", ""
+}
+
+func (s *messageService) markFailed(ctx context.Context, msg domain.Message, reason string) {
+	from := msg.Status
+	if err := msg.MarkFailed(reason, time.Now()); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to mark message as failed", "error", err)
+		return
+	}
+	if err := s.repo.UpdateMessageStatus(ctx, msg, from); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to mark message as failed", "error", err)
+		return
+	}
+	s.notify(ctx, msg)
+
+	// The sender only picks up parents, so parts left pending would stay pending for good
+	s.settleParts(ctx, msg, func(part *domain.Message, now time.Time) error {
+		return part.MarkFailed(reason, now)
+	})
+}
+
+// settleParts moves the unsent parts of a multipart message that won't be sent out of
+// pending with mark. Failures are logged; the parent's status is already persisted.
+func (s *messageService) settleParts(ctx context.Context, msg domain.Message,
+	mark func(part *domain.Message, now time.Time) error) {
+	now := time.Now()
+	for _, part := range msg.Parts {
+		if part.Status != domain.StatusPending {
+			continue
+		}
+		if err := mark(&part, now); err != nil {
+			logging.ForMessage(part).WarnContext(ctx, "Failed to settle unsent part", "error", err)
+			continue
+		}
+		if err := s.repo.UpdateMessageStatus(ctx, part, domain.StatusPending); err != nil {
+			logging.ForMessage(part).WarnContext(ctx, "Failed to settle unsent part", "error", err)
+		}
+	}
+}
+
+// quietUntil reports when msg's category may next be sent if its recipient is currently
+// inside the category's quiet hours
+func (s *messageService) quietUntil(msg domain.Message) (time.Time, bool) {
+	window, ok := s.quietHours[msg.Category]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	zone := msg.Timezone
+	if zone == "" {
+		zone = phone.Timezone(msg.CountryCode)
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil || zone == "" {
+		loc = time.UTC
+	}
+
+	return window.OpensAt(time.Now(), loc)
+}
+
+func (s *messageService) markSuppressed(ctx context.Context, msg domain.Message) {
+	from := msg.Status
+	if err := msg.MarkSuppressed(time.Now()); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to mark message as suppressed", "error", err)
+		return
+	}
+	if err := s.repo.UpdateMessageStatus(ctx, msg, from); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to mark message as suppressed", "error", err)
+		return
+	}
+	s.notify(ctx, msg)
+	s.settleParts(ctx, msg, func(part *domain.Message, now time.Time) error {
+		return part.MarkSuppressed(now)
+	})
+}
+
+// notify never fails the caller; the status change is already persisted
+func (s *messageService) notify(ctx context.Context, msg domain.Message) {
+	if err := s.notifier.Notify(ctx, msg); err != nil {
+		logging.ForMessage(msg).ErrorContext(ctx, "Failed to queue status callback", "error", err)
+	}
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) Notify(context.Context, domain.Message) error { return nil }
+
+type noopMetrics struct{}
+
+func (noopMetrics) MessageSent()                  {}
+func (noopMetrics) MessageFailed(error)           {}
+func (noopMetrics) MessageRetried(error)          {}
+func (noopMetrics) ObserveTick(time.Duration)     {}
+func (noopMetrics) SetSenderRunning(running bool) {}
+
+// staticConfig is the zero configuration, for a service built without WithConfig
+type staticConfig struct{}
+
+func (staticConfig) Current() config.Config { return config.Config{} }
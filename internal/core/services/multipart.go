@@ -0,0 +1,77 @@
+package services
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/sms"
+)
+
+type MultipartMode string
+
+const (
+	// MultipartUDH sends each part with concatenation metadata so the handset reassembles them
+	MultipartUDH MultipartMode = "udh"
+	// MultipartSuffix sends standalone texts numbered with a "(1/3)" suffix
+	MultipartSuffix MultipartMode = "suffix"
+)
+
+// splitMessage fills msg.Parts when its content needs more than one segment
+func splitMessage(msg *domain.Message, mode MultipartMode) {
+	info := sms.Analyze(msg.Content)
+	if info.Segments <= 1 {
+		return
+	}
+
+	var chunks []string
+	concatRef := 0
+	switch mode {
+	case MultipartUDH:
+		chunks = sms.Split(msg.Content, info.Encoding, info.PerSegment)
+		concatRef = rand.IntN(255) + 1
+	default:
+		chunks = splitWithSuffix(msg.Content, info)
+	}
+
+	msg.Parts = make([]domain.Message, len(chunks))
+	for i, chunk := range chunks {
+		partInfo := sms.Analyze(chunk)
+		msg.Parts[i] = domain.Message{
+			TenantID:    msg.TenantID,
+			To:          msg.To,
+			CountryCode: msg.CountryCode,
+			Content:     chunk,
+			Status:      domain.StatusPending,
+			Encoding:    string(partInfo.Encoding),
+			Segments:    partInfo.Segments,
+			PartNumber:  i + 1,
+			PartTotal:   len(chunks),
+			ConcatRef:   concatRef,
+		}
+	}
+	msg.PartTotal = len(chunks)
+	msg.ConcatRef = concatRef
+}
+
+// splitWithSuffix leaves room in each standalone segment for its " (i/n)" suffix. The
+// suffix width depends on the part count, so split again until the count settles.
+func splitWithSuffix(content string, info sms.Info) []string {
+	capacity := sms.SingleCapacity(info.Encoding)
+	total := info.Segments
+
+	for {
+		chunks := sms.Split(content, info.Encoding, capacity-len(partSuffix(total, total)))
+		if len(chunks) <= total {
+			for i := range chunks {
+				chunks[i] += partSuffix(i+1, len(chunks))
+			}
+			return chunks
+		}
+		total = len(chunks)
+	}
+}
+
+func partSuffix(number, total int) string {
+	return fmt.Sprintf(" (%d/%d)", number, total)
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type rateLimitedSender struct {
+	next    ports.MessageSender
+	limiter ports.RateLimiter
+}
+
+// NewRateLimitedSender holds sends back with a domain.DeferredError while the
+// limiter has no capacity left for the recipient
+func NewRateLimitedSender(sender ports.MessageSender, limiter ports.RateLimiter) ports.MessageSender {
+	return &rateLimitedSender{
+		next:    sender,
+		limiter: limiter,
+	}
+}
+
+func (r *rateLimitedSender) Send(ctx context.Context, message domain.Message) (string, error) {
+	wait, err := r.limiter.Reserve(ctx, message.To)
+	if err != nil {
+		// Without the limiter we can't honour the provider contract, so hold the message back
+		return "", &domain.DeferredError{Reason: fmt.Errorf("rate limiter unavailable: %w", err)}
+	}
+	if wait > 0 {
+		return "", &domain.DeferredError{Reason: domain.ErrRateLimited, RetryAfter: wait}
+	}
+
+	return r.next.Send(ctx, message)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/phone"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type recipientService struct {
+	repo          ports.RecipientRepository
+	defaultRegion string
+}
+
+// NewRecipientService creates a recipient service; phone numbers are normalized the same
+// way as message recipients so preferences match the stored To
+func NewRecipientService(repo ports.RecipientRepository, defaultRegion string) ports.RecipientService {
+	return &recipientService{
+		repo:          repo,
+		defaultRegion: defaultRegion,
+	}
+}
+
+func (s *recipientService) GetRecipient(ctx context.Context, number string) (*domain.Recipient, error) {
+	normalized, err := phone.Normalize(number, s.defaultRegion)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidRecipient, err)
+	}
+	return s.repo.GetRecipient(ctx, normalized)
+}
+
+func (s *recipientService) SavePreferences(ctx context.Context, recipient *domain.Recipient) error {
+	normalized, err := phone.Normalize(recipient.Phone, s.defaultRegion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRecipient, err)
+	}
+	recipient.Phone = normalized
+
+	if recipient.Locale != "" {
+		locale := domain.NormalizeLocale(recipient.Locale)
+		if locale == "" {
+			return fmt.Errorf("%w: locale %q is not a valid language tag", domain.ErrInvalidRecipient, recipient.Locale)
+		}
+		recipient.Locale = locale
+	}
+
+	if recipient.Timezone != "" {
+		if _, err := time.LoadLocation(recipient.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidRecipient, recipient.Timezone)
+		}
+	}
+
+	return s.repo.UpsertRecipient(ctx, recipient)
+}
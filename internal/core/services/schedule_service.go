@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+	"github.com/hasElvin/messenger-svc/internal/core/phone"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"github.com/robfig/cron/v3"
+)
+
+const scheduleBatchSize = 20
+
+type scheduleService struct {
+	repo          ports.ScheduleRepository
+	messages      ports.MessageEnqueuer
+	defaultRegion string
+}
+
+// NewScheduleService creates the recurring schedule service. Occurrences are materialized
+// through messages, so scheduled messages get the same validation as API ones.
+func NewScheduleService(repo ports.ScheduleRepository, messages ports.MessageEnqueuer,
+	defaultRegion string) ports.ScheduleService {
+	return &scheduleService{
+		repo:          repo,
+		messages:      messages,
+		defaultRegion: defaultRegion,
+	}
+}
+
+func (s *scheduleService) CreateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	if err := s.prepareSchedule(schedule, time.Now()); err != nil {
+		return err
+	}
+	return s.repo.CreateSchedule(ctx, schedule)
+}
+
+func (s *scheduleService) GetSchedule(ctx context.Context, id uint) (*domain.Schedule, error) {
+	return s.repo.GetSchedule(ctx, id)
+}
+
+func (s *scheduleService) ListSchedules(ctx context.Context) ([]domain.Schedule, error) {
+	return s.repo.ListSchedules(ctx)
+}
+
+// UpdateSchedule replaces a schedule and recomputes its next run from now
+func (s *scheduleService) UpdateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	if err := s.prepareSchedule(schedule, time.Now()); err != nil {
+		return err
+	}
+	return s.repo.UpdateSchedule(ctx, schedule)
+}
+
+func (s *scheduleService) DeleteSchedule(ctx context.Context, id uint) error {
+	return s.repo.DeleteSchedule(ctx, id)
+}
+
+func (s *scheduleService) PreviewRuns(ctx context.Context, id uint, count int) ([]time.Time, error) {
+	schedule, err := s.repo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, loc, err := parseSchedule(*schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]time.Time, 0, count)
+	after := time.Now()
+	for len(runs) < count {
+		next := nextRun(*schedule, spec, loc, after)
+		if next == nil {
+			break
+		}
+		runs = append(runs, *next)
+		after = *next
+	}
+	return runs, nil
+}
+
+func (s *scheduleService) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.RunDueSchedules(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunDueSchedules materializes every due occurrence. Replicas race to advance each schedule
+// past its occurrence and only the winner enqueues, so an occurrence is never sent twice.
+// After downtime a schedule fires once for the missed occurrences, not once per occurrence.
+func (s *scheduleService) RunDueSchedules(ctx context.Context) {
+	now := time.Now()
+	schedules, err := s.repo.ListDueSchedules(ctx, now, scheduleBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to list due schedules", "error", err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		spec, loc, err := parseSchedule(schedule)
+		if err != nil {
+			slog.ErrorContext(ctx, "Schedule is no longer valid, skipping", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+
+		due := *schedule.NextRunAt
+		won, err := s.repo.AdvanceSchedule(ctx, schedule.ID, due, nextRun(schedule, spec, loc, now))
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to advance schedule", "schedule_id", schedule.ID, "error", err)
+			continue
+		}
+		if !won {
+			continue
+		}
+
+		// The occurrence is claimed now, so enqueue it in full even if shutdown begins meanwhile
+		s.materialize(context.WithoutCancel(ctx), schedule, due)
+	}
+}
+
+// materialize enqueues the occurrence for every recipient; one bad recipient doesn't
+// hold up the rest
+func (s *scheduleService) materialize(ctx context.Context, schedule domain.Schedule, due time.Time) {
+	for _, recipient := range schedule.Recipients {
+		msg := domain.Message{
+			To:         recipient,
+			TemplateID: schedule.TemplateID,
+			Variables:  schedule.Variables,
+			Locale:     schedule.Locale,
+			Category:   schedule.Category,
+		}
+		if err := s.messages.EnqueueMessage(ctx, &msg); err != nil {
+			slog.ErrorContext(ctx, "Schedule failed to enqueue a message", "schedule_id", schedule.ID,
+				"run", due, "recipient", logging.MaskPhone(recipient), "error", err)
+		}
+	}
+}
+
+func (s *scheduleService) prepareSchedule(schedule *domain.Schedule, now time.Time) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidSchedule)
+	}
+	if schedule.TemplateID == 0 {
+		return fmt.Errorf("%w: template_id is required", domain.ErrInvalidSchedule)
+	}
+	if schedule.StartAt != nil && schedule.EndAt != nil && !schedule.EndAt.After(*schedule.StartAt) {
+		return fmt.Errorf("%w: end_at must be after start_at", domain.ErrInvalidSchedule)
+	}
+
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	spec, loc, err := parseSchedule(*schedule)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Locale != "" {
+		locale := domain.NormalizeLocale(schedule.Locale)
+		if locale == "" {
+			return fmt.Errorf("%w: locale %q is not a valid language tag", domain.ErrInvalidSchedule, schedule.Locale)
+		}
+		schedule.Locale = locale
+	}
+	schedule.Category = strings.ToLower(strings.TrimSpace(schedule.Category))
+
+	if len(schedule.Recipients) == 0 {
+		return fmt.Errorf("%w: at least one recipient is required", domain.ErrInvalidSchedule)
+	}
+	recipients := make([]string, 0, len(schedule.Recipients))
+	seen := make(map[string]bool, len(schedule.Recipients))
+	for _, raw := range schedule.Recipients {
+		normalized, err := phone.Normalize(raw, s.defaultRegion)
+		if err != nil {
+			return fmt.Errorf("%w: recipient %q: %v", domain.ErrInvalidSchedule, raw, err)
+		}
+		if !seen[normalized] {
+			seen[normalized] = true
+			recipients = append(recipients, normalized)
+		}
+	}
+	schedule.Recipients = recipients
+
+	schedule.NextRunAt = nextRun(*schedule, spec, loc, now)
+	return nil
+}
+
+// parseSchedule parses the cron expression and timezone; the timezone only comes from its
+// own field so a CRON_TZ prefix can't silently disagree with it
+func parseSchedule(schedule domain.Schedule) (cron.Schedule, *time.Location, error) {
+	expr := strings.TrimSpace(schedule.Cron)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		return nil, nil, fmt.Errorf("%w: set the timezone field instead of a TZ prefix", domain.ErrInvalidSchedule)
+	}
+
+	spec, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: cron: %v", domain.ErrInvalidSchedule, err)
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidSchedule, schedule.Timezone)
+	}
+	return spec, loc, nil
+}
+
+// nextRun returns the first occurrence after after within the schedule's start and end
+// dates, or nil if there is none
+func nextRun(schedule domain.Schedule, spec cron.Schedule, loc *time.Location, after time.Time) *time.Time {
+	// cron rounds up to the next whole second, so this lets StartAt itself be an occurrence
+	if schedule.StartAt != nil && !after.After(*schedule.StartAt) {
+		after = schedule.StartAt.Add(-time.Nanosecond)
+	}
+
+	next := spec.Next(after.In(loc))
+	if next.IsZero() || (schedule.EndAt != nil && next.After(*schedule.EndAt)) {
+		return nil
+	}
+	return &next
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"sync/atomic"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+)
+
+// senderCounters tallies dispatch outcomes for the sender status without taking the service lock
+type senderCounters struct {
+	sent       atomic.Int64
+	failed     atomic.Int64
+	retried    atomic.Int64
+	deferred   atomic.Int64
+	suppressed atomic.Int64
+	duplicates atomic.Int64
+}
+
+func (c *senderCounters) reset() {
+	for _, counter := range []*atomic.Int64{&c.sent, &c.failed, &c.retried, &c.deferred, &c.suppressed, &c.duplicates} {
+		counter.Store(0)
+	}
+}
+
+func (c *senderCounters) snapshot() domain.SenderCounters {
+	return domain.SenderCounters{
+		Sent:       c.sent.Load(),
+		Failed:     c.failed.Load(),
+		Retried:    c.retried.Load(),
+		Deferred:   c.deferred.Load(),
+		Suppressed: c.suppressed.Load(),
+		Duplicates: c.duplicates.Load(),
+	}
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+	"github.com/hasElvin/messenger-svc/internal/core/phone"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+const (
+	suppressionCachePrefix = "suppressed:"
+	// Entries are written through on every change, so the TTL only bounds how long a
+	// change made directly in the database can go unnoticed
+	suppressionCacheTTL = 10 * time.Minute
+)
+
+// DefaultOptOutKeywords are the STOP-style replies that opt a number out
+var DefaultOptOutKeywords = []string{"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT", "OPTOUT", "IPTAL"}
+
+// DefaultOptInKeywords are the replies that lift a keyword opt-out
+var DefaultOptInKeywords = []string{"START", "UNSTOP"}
+
+type suppressionService struct {
+	repo           ports.SuppressionRepository
+	cache          ports.CacheService
+	defaultRegion  string
+	optOutKeywords map[string]bool
+	optInKeywords  map[string]bool
+}
+
+// NewSuppressionService creates the opt-out list service. Empty keyword lists fall back
+// to DefaultOptOutKeywords and DefaultOptInKeywords.
+func NewSuppressionService(repo ports.SuppressionRepository, cache ports.CacheService,
+	defaultRegion string, optOutKeywords, optInKeywords []string) ports.SuppressionService {
+
+	if len(optOutKeywords) == 0 {
+		optOutKeywords = DefaultOptOutKeywords
+	}
+	if len(optInKeywords) == 0 {
+		optInKeywords = DefaultOptInKeywords
+	}
+
+	return &suppressionService{
+		repo:           repo,
+		cache:          cache,
+		defaultRegion:  defaultRegion,
+		optOutKeywords: keywordSet(optOutKeywords),
+		optInKeywords:  keywordSet(optInKeywords),
+	}
+}
+
+func (s *suppressionService) IsSuppressed(ctx context.Context, number string) (bool, error) {
+	key := suppressionCachePrefix + number
+
+	cached, err := s.cache.Get(ctx, key)
+	if err == nil {
+		return cached == "1", nil
+	}
+	if !errors.Is(err, domain.ErrCacheMiss) {
+		slog.WarnContext(ctx, "Suppression cache lookup failed, using database",
+			"recipient", logging.MaskPhone(number), "error", err)
+	}
+
+	_, err = s.repo.GetSuppression(ctx, number)
+	suppressed := err == nil
+	if err != nil && !errors.Is(err, domain.ErrSuppressionNotFound) {
+		return false, err
+	}
+
+	s.cacheSuppressed(ctx, number, suppressed)
+	return suppressed, nil
+}
+
+func (s *suppressionService) Suppress(ctx context.Context, suppression *domain.Suppression) error {
+	normalized, err := phone.Normalize(suppression.Phone, s.defaultRegion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRecipient, err)
+	}
+	suppression.Phone = normalized
+	if suppression.Source == "" {
+		suppression.Source = domain.SuppressionManual
+	}
+
+	if err := s.repo.AddSuppression(ctx, suppression); err != nil {
+		return err
+	}
+	s.cacheSuppressed(ctx, normalized, true)
+	return nil
+}
+
+func (s *suppressionService) Unsuppress(ctx context.Context, number string) error {
+	normalized, err := phone.Normalize(number, s.defaultRegion)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidRecipient, err)
+	}
+
+	if err := s.repo.RemoveSuppression(ctx, normalized); err != nil {
+		return err
+	}
+	s.cacheSuppressed(ctx, normalized, false)
+	return nil
+}
+
+func (s *suppressionService) ListSuppressions(ctx context.Context) ([]domain.Suppression, error) {
+	return s.repo.ListSuppressions(ctx)
+}
+
+func (s *suppressionService) HandleInbound(ctx context.Context,
+	inbound domain.InboundMessage) (domain.InboundAction, error) {
+
+	action := s.classify(inbound.Text)
+	if action == domain.InboundIgnore {
+		return action, nil
+	}
+
+	normalized, err := phone.Normalize(inbound.From, s.defaultRegion)
+	if err != nil {
+		return domain.InboundIgnore, fmt.Errorf("%w: %v", domain.ErrInvalidRecipient, err)
+	}
+
+	if action == domain.InboundOptOut {
+		err := s.Suppress(ctx, &domain.Suppression{
+			Phone:  normalized,
+			Source: domain.SuppressionKeyword,
+			Reason: strings.TrimSpace(inbound.Text),
+		})
+		return action, err
+	}
+
+	// A START reply only lifts opt-outs the recipient made by keyword themselves;
+	// numbers suppressed by an operator stay suppressed
+	existing, err := s.repo.GetSuppression(ctx, normalized)
+	if errors.Is(err, domain.ErrSuppressionNotFound) {
+		return domain.InboundIgnore, nil
+	}
+	if err != nil {
+		return domain.InboundIgnore, err
+	}
+	if existing.Source != domain.SuppressionKeyword {
+		return domain.InboundIgnore, nil
+	}
+
+	if err := s.repo.RemoveSuppression(ctx, normalized); err != nil && !errors.Is(err, domain.ErrSuppressionNotFound) {
+		return domain.InboundIgnore, err
+	}
+	s.cacheSuppressed(ctx, normalized, false)
+	return action, nil
+}
+
+// classify only acts on a reply that is a keyword by itself, give or take surrounding
+// punctuation and case, so "STOP", "stop!" and "İptal." count but a conversational reply
+// like "How do I cancel my booking?" doesn't.
+func (s *suppressionService) classify(text string) domain.InboundAction {
+	reply := normalizeKeyword(strings.TrimFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}))
+
+	switch {
+	case s.optOutKeywords[reply]:
+		return domain.InboundOptOut
+	case s.optInKeywords[reply]:
+		return domain.InboundOptIn
+	default:
+		return domain.InboundIgnore
+	}
+}
+
+// cacheSuppressed never fails the caller; the database stays the source of truth
+func (s *suppressionService) cacheSuppressed(ctx context.Context, number string, suppressed bool) {
+	value := "0"
+	if suppressed {
+		value = "1"
+	}
+	if err := s.cache.SetWithTTL(ctx, suppressionCachePrefix+number, value, suppressionCacheTTL); err != nil {
+		slog.WarnContext(ctx, "Failed to cache suppression state", "recipient", logging.MaskPhone(number), "error", err)
+	}
+}
+
+func keywordSet(keywords []string) map[string]bool {
+	set := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		set[normalizeKeyword(keyword)] = true
+	}
+	return set
+}
+
+// normalizeKeyword upper-cases with Turkish dotted and dotless i folded to I, so
+// "iptal", "İPTAL" and "IPTAL" all match, and collapses runs of whitespace
+func normalizeKeyword(word string) string {
+	word = strings.Join(strings.Fields(word), " ")
+	return strings.ToUpper(strings.NewReplacer("ı", "i", "İ", "I").Replace(word))
+}
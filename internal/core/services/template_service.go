@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+)
+
+type templateService struct {
+	repo          ports.TemplateRepository
+	defaultLocale string
+	fallbacks     map[string][]string
+}
+
+// NewTemplateService creates a template service. Templates created without a locale are
+// written in defaultLocale; fallbacks maps a locale to the locales tried after it, e.g.
+// az -> [tr, en], and defaultLocale is tried last.
+func NewTemplateService(repo ports.TemplateRepository, defaultLocale string,
+	fallbacks map[string][]string) ports.TemplateService {
+
+	normalized := make(map[string][]string, len(fallbacks))
+	for locale, chain := range fallbacks {
+		normalized[domain.NormalizeLocale(locale)] = chain
+	}
+
+	defaultLocale = domain.NormalizeLocale(defaultLocale)
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+
+	return &templateService{
+		repo:          repo,
+		defaultLocale: defaultLocale,
+		fallbacks:     normalized,
+	}
+}
+
+func (s *templateService) CreateTemplate(ctx context.Context, template *domain.Template) error {
+	if err := s.prepareTemplate(template); err != nil {
+		return err
+	}
+	return s.repo.CreateTemplate(ctx, template)
+}
+
+func (s *templateService) GetTemplate(ctx context.Context, id uint, version int) (*domain.Template, error) {
+	return s.repo.GetTemplate(ctx, id, version)
+}
+
+func (s *templateService) ListTemplates(ctx context.Context) ([]domain.Template, error) {
+	return s.repo.ListTemplates(ctx)
+}
+
+func (s *templateService) UpdateTemplate(ctx context.Context, template *domain.Template) error {
+	if err := s.prepareTemplate(template); err != nil {
+		return err
+	}
+	return s.repo.UpdateTemplate(ctx, template)
+}
+
+func (s *templateService) DeleteTemplate(ctx context.Context, id uint) error {
+	return s.repo.DeleteTemplate(ctx, id)
+}
+
+func (s *templateService) ListTemplateVersions(ctx context.Context, id uint) ([]domain.TemplateVersion, error) {
+	return s.repo.ListTemplateVersions(ctx, id)
+}
+
+func (s *templateService) Render(ctx context.Context, id uint, version int, locale string,
+	vars map[string]string) (domain.RenderedTemplate, error) {
+
+	template, err := s.repo.GetTemplate(ctx, id, version)
+	if err != nil {
+		return domain.RenderedTemplate{}, err
+	}
+
+	return template.Render(domain.LocaleChain(locale, s.fallbacks, s.defaultLocale), vars)
+}
+
+// prepareTemplate validates a template and its variants and works out its variables
+// from the default body. Variants may only use variables the default body uses, so a
+// caller that satisfies the template can render any locale.
+func (s *templateService) prepareTemplate(template *domain.Template) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return fmt.Errorf("%w: name is required", domain.ErrInvalidTemplate)
+	}
+
+	if template.Locale == "" {
+		template.Locale = s.defaultLocale
+	}
+	template.Locale = domain.NormalizeLocale(template.Locale)
+	if template.Locale == "" {
+		return fmt.Errorf("%w: locale is not a valid language tag", domain.ErrInvalidTemplate)
+	}
+
+	variables, err := domain.ParsePlaceholders(template.Body)
+	if err != nil {
+		return err
+	}
+	template.Variables = variables
+
+	variants := make(map[string]string, len(template.Variants))
+	for tag, body := range template.Variants {
+		locale := domain.NormalizeLocale(tag)
+		if locale == "" {
+			return fmt.Errorf("%w: variant locale %q is not a valid language tag", domain.ErrInvalidTemplate, tag)
+		}
+		if locale == template.Locale {
+			return fmt.Errorf("%w: variant %s duplicates the template locale", domain.ErrInvalidTemplate, locale)
+		}
+
+		names, err := domain.ParsePlaceholders(body)
+		if err != nil {
+			return fmt.Errorf("variant %s: %w", locale, err)
+		}
+		for _, name := range names {
+			if !slices.Contains(variables, name) {
+				return fmt.Errorf("%w: variant %s uses {{%s}} which the %s body doesn't",
+					domain.ErrInvalidTemplate, locale, name, template.Locale)
+			}
+		}
+		variants[locale] = body
+	}
+	template.Variants = variants
+	return nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/hasElvin/messenger-svc/internal/core/services"
+
+// traceParent serializes the span in ctx as a W3C traceparent, empty if there is none
+func traceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// enqueueLink links a dispatch span to the request that enqueued msg
+func enqueueLink(msg domain.Message) (trace.Link, bool) {
+	if msg.TraceParent == "" {
+		return trace.Link{}, false
+	}
+
+	ctx := propagation.TraceContext{}.Extract(context.Background(),
+		propagation.MapCarrier{"traceparent": msg.TraceParent})
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return trace.Link{}, false
+	}
+
+	return trace.Link{
+		SpanContext: spanContext,
+		Attributes:  []attribute.KeyValue{attribute.Int64("message.id", int64(msg.ID))},
+	}, true
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
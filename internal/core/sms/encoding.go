@@ -0,0 +1,172 @@
+// Package sms works out how message content is encoded on the air interface and how many
+// segments it takes, following the GSM 03.38 / 3GPP TS 23.038 alphabets.
+package sms
+
+import (
+	"math"
+	"unicode/utf16"
+)
+
+type Encoding string
+
+const (
+	// GSM7 uses the default alphabet and its extension table
+	GSM7 Encoding = "gsm7"
+	// GSM7Turkish uses the default alphabet with the Turkish national single shift table,
+	// which costs an extra user data header element in every segment
+	GSM7Turkish Encoding = "gsm7-turkish"
+	// UCS2 is used whenever a character has no GSM-7 representation
+	UCS2 Encoding = "ucs2"
+)
+
+const (
+	singleSegmentOctets = 140
+
+	// Concatenation element: IEI + IEDL + reference, total and sequence octets
+	concatIEOctets = 5
+	// National language single shift element: IEI + IEDL + language
+	shiftIEOctets = 3
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet
+var gsm7Basic = runeSet("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// gsm7Extension is the default extension table; each character costs an escape plus itself
+var gsm7Extension = runeSet("\f^{}\\[~]|€")
+
+// turkishSingleShift is the Turkish national language single shift table (3GPP TS 23.038 A.2.1)
+var turkishSingleShift = runeSet("\f^{}\\[~]|ĞİŞç€ğış")
+
+// Info describes how a piece of content will be sent
+type Info struct {
+	Encoding Encoding `json:"encoding"`
+	// Units is septets for GSM-7 and UTF-16 code units for UCS-2
+	Units    int `json:"units"`
+	Segments int `json:"segments"`
+	// PerSegment is the capacity of each segment in Units for this content
+	PerSegment int `json:"per_segment"`
+}
+
+// Analyze picks the cheapest encoding able to represent content and counts its segments
+func Analyze(content string) Info {
+	switch {
+	case fitsGSM7(content, gsm7Extension):
+		return gsm7Info(content, GSM7, 0)
+	case fitsGSM7(content, turkishSingleShift):
+		return gsm7Info(content, GSM7Turkish, shiftIEOctets)
+	default:
+		return ucs2Info(content)
+	}
+}
+
+// SingleCapacity is how many units fit in one standalone, non-concatenated segment
+func SingleCapacity(encoding Encoding) int {
+	switch encoding {
+	case GSM7:
+		return septetCapacity(0)
+	case GSM7Turkish:
+		return septetCapacity(shiftIEOctets)
+	default:
+		return singleSegmentOctets / 2
+	}
+}
+
+// Split breaks content into chunks of at most capacity units in the given encoding,
+// never splitting an escaped GSM-7 character or a UTF-16 surrogate pair
+func Split(content string, encoding Encoding, capacity int) []string {
+	var parts []string
+	runes := []rune(content)
+	start, used := 0, 0
+	for i, r := range runes {
+		cost := unitCost(r, encoding)
+		if used+cost > capacity && i > start {
+			parts = append(parts, string(runes[start:i]))
+			start, used = i, 0
+		}
+		used += cost
+	}
+
+	return append(parts, string(runes[start:]))
+}
+
+func gsm7Info(content string, encoding Encoding, elementOctets int) Info {
+	septets := 0
+	for _, r := range content {
+		septets += unitCost(r, encoding)
+	}
+
+	single := septetCapacity(elementOctets)
+	if septets <= single {
+		return Info{Encoding: encoding, Units: septets, Segments: 1, PerSegment: single}
+	}
+
+	// Escape sequences can't straddle segments, so count by packing rather than dividing
+	perSegment := septetCapacity(elementOctets + concatIEOctets)
+	segments := len(Split(content, encoding, perSegment))
+
+	return Info{Encoding: encoding, Units: septets, Segments: segments, PerSegment: perSegment}
+}
+
+func ucs2Info(content string) Info {
+	units := len(utf16.Encode([]rune(content)))
+
+	single := singleSegmentOctets / 2
+	if units <= single {
+		return Info{Encoding: UCS2, Units: units, Segments: 1, PerSegment: single}
+	}
+
+	perSegment := (singleSegmentOctets - concatIEOctets - 1) / 2
+	segments := len(Split(content, UCS2, perSegment))
+
+	return Info{Encoding: UCS2, Units: units, Segments: segments, PerSegment: perSegment}
+}
+
+// septetCapacity is how many septets fit in one segment whose user data header
+// carries elementOctets worth of information elements
+func septetCapacity(elementOctets int) int {
+	if elementOctets == 0 {
+		return singleSegmentOctets * 8 / 7
+	}
+	// The header length octet counts towards the header, which is padded to a septet boundary
+	headerSeptets := int(math.Ceil(float64((elementOctets+1)*8) / 7))
+	return singleSegmentOctets*8/7 - headerSeptets
+}
+
+func fitsGSM7(content string, shift map[rune]bool) bool {
+	for _, r := range content {
+		if !gsm7Basic[r] && !shift[r] {
+			return false
+		}
+	}
+	return true
+}
+
+func gsm7Cost(r rune, shift map[rune]bool) int {
+	if gsm7Basic[r] {
+		return 1
+	}
+	if shift[r] {
+		return 2
+	}
+	return 0
+}
+
+func unitCost(r rune, encoding Encoding) int {
+	switch encoding {
+	case GSM7:
+		return gsm7Cost(r, gsm7Extension)
+	case GSM7Turkish:
+		return gsm7Cost(r, turkishSingleShift)
+	default:
+		return utf16.RuneLen(r)
+	}
+}
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool, len(chars))
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
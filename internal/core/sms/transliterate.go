@@ -0,0 +1,54 @@
+package sms
+
+import "strings"
+
+// transliterations maps characters outside the GSM-7 default alphabet to the closest
+// characters inside it. Letters that already exist in the default alphabet (é, ü, Ç, ñ...)
+// are left alone since they cost nothing extra.
+var transliterations = map[rune]string{
+	// Turkish
+	'ş': "s", 'Ş': "S", 'ğ': "g", 'Ğ': "G", 'ı': "i", 'İ': "I", 'ç': "c",
+	// Azerbaijani
+	'ə': "e", 'Ə': "E",
+	// Other Latin accents
+	'á': "a", 'â': "a", 'ã': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'Á': "A", 'Â': "A", 'Ã': "A", 'À': "A", 'Ā': "A", 'Ă': "A", 'Ą': "A",
+	'ć': "c", 'č': "c", 'Ć': "C", 'Č': "C",
+	'ď': "d", 'Ď': "D", 'đ': "d", 'Đ': "D",
+	'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'Ê': "E", 'Ë': "E", 'È': "E", 'Ē': "E", 'Ė': "E", 'Ę': "E", 'Ě': "E",
+	'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i",
+	'Í': "I", 'Î': "I", 'Ï': "I", 'Ì': "I", 'Ī': "I", 'Į': "I",
+	'ł': "l", 'Ł': "L", 'ľ': "l", 'Ľ': "L",
+	'ń': "n", 'ň': "n", 'Ń': "N", 'Ň': "N",
+	'ó': "o", 'ô': "o", 'õ': "o", 'ō': "o", 'ő': "ö",
+	'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ò': "O", 'Ō': "O", 'Ő': "Ö",
+	'ř': "r", 'Ř': "R",
+	'ś': "s", 'š': "s", 'ș': "s", 'Ś': "S", 'Š': "S", 'Ș': "S",
+	'ť': "t", 'ț': "t", 'ţ': "t", 'Ť': "T", 'Ț': "T", 'Ţ': "T",
+	'ú': "u", 'û': "u", 'ū': "u", 'ů': "u", 'ų': "u", 'ű': "ü",
+	'Ú': "U", 'Û': "U", 'Ù': "U", 'Ū': "U", 'Ů': "U", 'Ų': "U", 'Ű': "Ü",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y", 'Ÿ': "Y",
+	'ź': "z", 'ż': "z", 'ž': "z", 'Ź': "Z", 'Ż': "Z", 'Ž': "Z",
+	'œ': "oe", 'Œ': "OE",
+	// Punctuation that word processors like to insert
+	'‘': "'", '’': "'", '‚': "'", '“': "\"", '”': "\"", '„': "\"",
+	'–': "-", '—': "-", '…': "...", '\u00a0': " ", '•': "-",
+}
+
+// Transliterate replaces characters that would force UCS-2 or a national shift table
+// with GSM-7 default alphabet equivalents; everything else is kept as is
+func Transliterate(content string) string {
+	var b strings.Builder
+	b.Grow(len(content))
+
+	for _, r := range content {
+		if replacement, ok := transliterations[r]; ok {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
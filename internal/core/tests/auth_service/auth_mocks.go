@@ -0,0 +1,64 @@
+package auth_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type mockedAPIKeyRepo struct {
+	mock.Mock
+}
+
+type mockedAuditRepo struct {
+	mock.Mock
+}
+
+func (r *mockedAPIKeyRepo) CreateAPIKey(ctx context.Context, key *domain.APIKey) error {
+	args := r.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (r *mockedAPIKeyRepo) GetAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	args := r.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.APIKey), args.Error(1)
+}
+
+func (r *mockedAPIKeyRepo) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.APIKey), args.Error(1)
+}
+
+func (r *mockedAPIKeyRepo) RevokeAPIKey(ctx context.Context, id uint, at time.Time) error {
+	args := r.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (r *mockedAPIKeyRepo) TouchAPIKey(ctx context.Context, id uint, at time.Time) error {
+	args := r.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (r *mockedAuditRepo) RecordAudit(ctx context.Context, entry *domain.AuditEntry) error {
+	args := r.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (r *mockedAuditRepo) ListAudit(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	args := r.Called(ctx, filter)
+	return args.Get(0).([]domain.AuditEntry), args.Error(1)
+}
+
+// staticConfig serves a fixed configuration
+type staticConfig struct {
+	cfg config.Config
+}
+
+func (c *staticConfig) Current() config.Config {
+	return c.cfg
+}
@@ -0,0 +1,262 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+	"time"
+)
+
+func tenants() *staticConfig {
+	cfg := config.Config{}
+	cfg.Tenants = map[string]config.Tenant{"marketing": {}}
+	return &staticConfig{cfg: cfg}
+}
+
+func hashOf(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreateKey_StoresOnlyTheHash(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	keys := new(mockedAPIKeyRepo)
+	keys.On("CreateAPIKey", ctx, mock.AnythingOfType("*domain.APIKey")).Return(nil)
+
+	service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+	// Act
+	key, secret, err := service.CreateKey(ctx, " ci pipeline ", domain.RoleSend, "", nil)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "msk_"))
+	assert.Equal(t, "ci pipeline", key.Name)
+	assert.Equal(t, domain.RoleSend, key.Role)
+	assert.Equal(t, hashOf(secret), key.Hash)
+	assert.Equal(t, secret[:12], key.Prefix)
+	assert.NotContains(t, key.Hash, secret)
+}
+
+func TestCreateKey_RejectsUnknownRole(t *testing.T) {
+	// Arrange
+	keys := new(mockedAPIKeyRepo)
+	service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+	// Act
+	_, _, err := service.CreateKey(context.Background(), "ci", domain.Role("root"), "", nil)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	keys.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+func TestCreateKey_Tenant(t *testing.T) {
+	cases := map[string]struct {
+		tenantID string
+		role     domain.Role
+		err      error
+	}{
+		"send key":       {tenantID: "marketing", role: domain.RoleSend},
+		"read key":       {tenantID: "marketing", role: domain.RoleRead},
+		"operator key":   {tenantID: "marketing", role: domain.RoleOperator, err: domain.ErrInvalidAPIKey},
+		"unknown tenant": {tenantID: "support", role: domain.RoleSend, err: domain.ErrInvalidAPIKey},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			keys := new(mockedAPIKeyRepo)
+			keys.On("CreateAPIKey", ctx, mock.AnythingOfType("*domain.APIKey")).Return(nil)
+
+			service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+			// Act
+			key, _, err := service.CreateKey(ctx, "marketing app", tc.role, tc.tenantID, nil)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, tc.tenantID, key.TenantID)
+			} else {
+				keys.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-10 * time.Second)
+
+	cases := map[string]struct {
+		key     *domain.APIKey
+		err     error
+		touched bool
+	}{
+		"active":        {key: &domain.APIKey{ID: 1, Role: domain.RoleRead}, touched: true},
+		"used recently": {key: &domain.APIKey{ID: 1, Role: domain.RoleRead, LastUsedAt: &recent}},
+		"revoked":       {key: &domain.APIKey{ID: 1, Role: domain.RoleRead, RevokedAt: &past}, err: domain.ErrUnauthorized},
+		"expired":       {key: &domain.APIKey{ID: 1, Role: domain.RoleRead, ExpiresAt: &past}, err: domain.ErrUnauthorized},
+		"unknown":       {err: domain.ErrUnauthorized},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			keys := new(mockedAPIKeyRepo)
+			if tc.key != nil {
+				keys.On("GetAPIKeyByHash", ctx, hashOf("msk_secret")).Return(tc.key, nil)
+			} else {
+				keys.On("GetAPIKeyByHash", ctx, hashOf("msk_secret")).Return(nil, domain.ErrAPIKeyNotFound)
+			}
+			keys.On("TouchAPIKey", ctx, uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+
+			service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+			// Act
+			key, err := service.Authenticate(ctx, "msk_secret")
+
+			// Assert
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, tc.key, key)
+			}
+			if tc.touched {
+				keys.AssertCalled(t, "TouchAPIKey", ctx, uint(1), mock.AnythingOfType("time.Time"))
+			} else {
+				keys.AssertNotCalled(t, "TouchAPIKey", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_MissingKey(t *testing.T) {
+	// Arrange
+	keys := new(mockedAPIKeyRepo)
+	service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+	// Act
+	_, err := service.Authenticate(context.Background(), "")
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	keys.AssertNotCalled(t, "GetAPIKeyByHash", mock.Anything, mock.Anything)
+}
+
+func TestEnsureKey(t *testing.T) {
+	rawKey := strings.Repeat("k", 40)
+
+	t.Run("creates a missing key", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		keys := new(mockedAPIKeyRepo)
+		keys.On("GetAPIKeyByHash", ctx, hashOf(rawKey)).Return(nil, domain.ErrAPIKeyNotFound)
+		keys.On("CreateAPIKey", ctx, mock.MatchedBy(func(key *domain.APIKey) bool {
+			return key.Role == domain.RoleAdmin && key.Hash == hashOf(rawKey) && key.Prefix == "kkkk"
+		})).Return(nil)
+
+		service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+		// Act
+		err := service.EnsureKey(ctx, "bootstrap-admin", rawKey, domain.RoleAdmin)
+
+		// Assert
+		assert.NoError(t, err)
+		keys.AssertExpectations(t)
+	})
+
+	t.Run("keeps an existing key", func(t *testing.T) {
+		// Arrange
+		ctx := context.Background()
+		keys := new(mockedAPIKeyRepo)
+		keys.On("GetAPIKeyByHash", ctx, hashOf(rawKey)).Return(&domain.APIKey{ID: 1, Role: domain.RoleAdmin}, nil)
+
+		service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+		// Act
+		err := service.EnsureKey(ctx, "bootstrap-admin", rawKey, domain.RoleAdmin)
+
+		// Assert
+		assert.NoError(t, err)
+		keys.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a short key", func(t *testing.T) {
+		// Arrange
+		keys := new(mockedAPIKeyRepo)
+		service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+		// Act
+		err := service.EnsureKey(context.Background(), "bootstrap-admin", "short", domain.RoleAdmin)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+}
+
+func TestHasAdminKey(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name string
+		keys []domain.APIKey
+		want bool
+	}{
+		{"no keys", nil, false},
+		{"active admin", []domain.APIKey{{ID: 1, Role: domain.RoleAdmin}}, true},
+		{"only operators", []domain.APIKey{{ID: 1, Role: domain.RoleOperator}}, false},
+		{"revoked admin", []domain.APIKey{{ID: 1, Role: domain.RoleAdmin, RevokedAt: &past}}, false},
+		{"expired admin", []domain.APIKey{{ID: 1, Role: domain.RoleAdmin, ExpiresAt: &past}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			keys := new(mockedAPIKeyRepo)
+			keys.On("ListAPIKeys", ctx).Return(tt.keys, nil)
+			service := services.NewAuthService(keys, new(mockedAuditRepo), tenants())
+
+			// Act
+			hasAdmin, err := service.HasAdminKey(ctx)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, hasAdmin)
+		})
+	}
+}
+
+func TestListAudit_ClampsLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	audit := new(mockedAuditRepo)
+	audit.On("ListAudit", ctx, domain.AuditFilter{APIKeyID: 3, Limit: 1000}).Return([]domain.AuditEntry{}, nil)
+
+	service := services.NewAuthService(new(mockedAPIKeyRepo), audit, tenants())
+
+	// Act
+	_, err := service.ListAudit(ctx, domain.AuditFilter{APIKeyID: 3, Limit: 50000})
+
+	// Assert
+	assert.NoError(t, err)
+	audit.AssertExpectations(t)
+}
+
+func TestRoleAllows(t *testing.T) {
+	assert.True(t, domain.RoleAdmin.Allows(domain.RoleOperator))
+	assert.True(t, domain.RoleSend.Allows(domain.RoleRead))
+	assert.True(t, domain.RoleSend.Allows(domain.RoleSend))
+	assert.False(t, domain.RoleSend.Allows(domain.RoleOperator))
+	assert.False(t, domain.Role("").Allows(domain.RoleRead))
+}
@@ -0,0 +1,48 @@
+package callback_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type mockedCallbackRepo struct {
+	mock.Mock
+}
+
+type mockedCallbackPoster struct {
+	mock.Mock
+}
+
+func (r *mockedCallbackRepo) EnqueueCallback(ctx context.Context, callback *domain.StatusCallback) error {
+	args := r.Called(ctx, callback)
+	return args.Error(0)
+}
+
+func (r *mockedCallbackRepo) ClaimDueCallbacks(ctx context.Context,
+	limit int, lease time.Duration) ([]domain.StatusCallback, error) {
+
+	args := r.Called(ctx, limit, lease)
+	return args.Get(0).([]domain.StatusCallback), args.Error(1)
+}
+
+func (r *mockedCallbackRepo) MarkCallbackDelivered(ctx context.Context, id uint) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (r *mockedCallbackRepo) RescheduleCallback(ctx context.Context, id uint, next time.Time, lastErr string) error {
+	args := r.Called(ctx, id, next, lastErr)
+	return args.Error(0)
+}
+
+func (r *mockedCallbackRepo) MarkCallbackAbandoned(ctx context.Context, id uint, lastErr string) error {
+	args := r.Called(ctx, id, lastErr)
+	return args.Error(0)
+}
+
+func (p *mockedCallbackPoster) Post(ctx context.Context, url string, body []byte) error {
+	args := p.Called(ctx, url, body)
+	return args.Error(0)
+}
@@ -0,0 +1,97 @@
+package callback_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestNotify_WithoutCallbackURL(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	callbackRepo := new(mockedCallbackRepo)
+	callbackPoster := new(mockedCallbackPoster)
+
+	service := services.NewCallbackService(callbackRepo, callbackPoster, 3)
+
+	// Act
+	err := service.Notify(ctx, domain.Message{ID: 1, Status: domain.StatusSent})
+
+	// Assert
+	assert.NoError(t, err)
+	callbackRepo.AssertNotCalled(t, "EnqueueCallback", mock.Anything, mock.Anything)
+}
+
+func TestNotify_QueuesStatusEvent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	callbackRepo := new(mockedCallbackRepo)
+	callbackPoster := new(mockedCallbackPoster)
+
+	service := services.NewCallbackService(callbackRepo, callbackPoster, 3)
+
+	message := domain.Message{ID: 1, To: "+905551111001", Status: domain.StatusSent,
+		ProviderMessageID: "msg-12345", CallbackURL: "https://example.com/hooks/sms"}
+
+	callbackRepo.On("EnqueueCallback", ctx, mock.MatchedBy(func(callback *domain.StatusCallback) bool {
+		var event domain.StatusEvent
+		if err := json.Unmarshal(callback.Payload, &event); err != nil {
+			return false
+		}
+		return callback.MessageID == 1 && callback.URL == message.CallbackURL &&
+			event.Status == domain.StatusSent && event.ProviderMessageID == "msg-12345"
+	})).Return(nil)
+
+	// Act
+	err := service.Notify(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	callbackRepo.AssertExpectations(t)
+}
+
+func TestDispatchDueCallbacks(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	callbackRepo := new(mockedCallbackRepo)
+	callbackPoster := new(mockedCallbackPoster)
+
+	service := services.NewCallbackService(callbackRepo, callbackPoster, 3)
+
+	callbacks := []domain.StatusCallback{
+		{ID: 1, MessageID: 1, URL: "https://a.example.com", Payload: []byte(`{}`), Attempts: 1}, // Delivered
+		{ID: 2, MessageID: 2, URL: "https://b.example.com", Payload: []byte(`{}`), Attempts: 1}, // Retried later
+		{ID: 3, MessageID: 3, URL: "https://c.example.com", Payload: []byte(`{}`), Attempts: 3}, // Out of attempts
+	}
+
+	callbackRepo.On("ClaimDueCallbacks", ctx, mock.Anything, mock.Anything).Return(callbacks, nil)
+	callbackPoster.On("Post", ctx, "https://a.example.com", mock.Anything).Return(nil)
+	callbackPoster.On("Post", ctx, "https://b.example.com", mock.Anything).Return(errors.New("503"))
+	callbackPoster.On("Post", ctx, "https://c.example.com", mock.Anything).Return(errors.New("503"))
+
+	callbackRepo.On("MarkCallbackDelivered", ctx, uint(1)).Return(nil)
+	callbackRepo.On("RescheduleCallback", ctx, uint(2), mock.MatchedBy(func(next time.Time) bool {
+		return next.After(time.Now())
+	}), "503").Return(nil)
+	callbackRepo.On("MarkCallbackAbandoned", ctx, uint(3), "503").Return(nil)
+
+	// Act
+	service.DispatchDueCallbacks(ctx)
+
+	// Assert
+	callbackRepo.AssertExpectations(t)
+	callbackPoster.AssertExpectations(t)
+}
+
+func TestCallbackBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Second, services.CallbackBackoff(1))
+	assert.Equal(t, 10*time.Second, services.CallbackBackoff(2))
+	assert.Equal(t, 40*time.Second, services.CallbackBackoff(4))
+	assert.Equal(t, time.Hour, services.CallbackBackoff(20))
+}
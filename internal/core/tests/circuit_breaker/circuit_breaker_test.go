@@ -0,0 +1,224 @@
+package circuit_breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"testing"
+	"time"
+)
+
+var providerDown = &domain.ProviderError{StatusCode: 503}
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	breaker := services.NewCircuitBreaker(messageSender, 2, time.Minute, 1)
+
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	messageSender.On("Send", ctx, message).Return("", providerDown).Twice()
+
+	// Act
+	_, firstErr := breaker.Send(ctx, message)
+	_, secondErr := breaker.Send(ctx, message)
+	_, rejectedErr := breaker.Send(ctx, message)
+
+	// Assert
+	assert.Equal(t, providerDown, firstErr)
+	assert.Equal(t, providerDown, secondErr)
+	assert.ErrorIs(t, rejectedErr, domain.ErrCircuitOpen)
+
+	status := breaker.Status()
+	assert.Equal(t, domain.BreakerOpen, status.State)
+	assert.Equal(t, int64(1), status.Trips)
+	assert.Equal(t, int64(1), status.Rejected)
+	assert.NotNil(t, status.RetryAt)
+
+	// The wrapped sender is not called while the breaker is open
+	messageSender.AssertNumberOfCalls(t, "Send", 2)
+}
+
+func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	breaker := services.NewCircuitBreaker(messageSender, 2, time.Minute, 1)
+
+	failing := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	working := domain.Message{ID: 2, To: "+905551111002", Content: "Test message 2"}
+	messageSender.On("Send", ctx, failing).Return("", providerDown)
+	messageSender.On("Send", ctx, working).Return("msg-id-2", nil)
+
+	// Act
+	_, _ = breaker.Send(ctx, failing)
+	_, _ = breaker.Send(ctx, working)
+	_, _ = breaker.Send(ctx, failing)
+
+	// Assert
+	status := breaker.Status()
+	assert.Equal(t, domain.BreakerClosed, status.State)
+	assert.Equal(t, 1, status.ConsecutiveFailures)
+}
+
+func TestCircuitBreaker_HalfOpenProbeCloses(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	breaker := services.NewCircuitBreaker(messageSender, 1, 20*time.Millisecond, 1)
+
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	messageSender.On("Send", ctx, message).Return("", providerDown).Once()
+	messageSender.On("Send", ctx, message).Return("msg-id-1", nil).Once()
+
+	// Act
+	_, _ = breaker.Send(ctx, message)
+	assert.Equal(t, domain.BreakerOpen, breaker.Status().State)
+
+	time.Sleep(30 * time.Millisecond)
+	messageID, err := breaker.Send(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "msg-id-1", messageID)
+	assert.Equal(t, domain.BreakerClosed, breaker.Status().State)
+	messageSender.AssertExpectations(t)
+}
+
+func TestCircuitBreaker_HalfOpenProbeFailureReopens(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	breaker := services.NewCircuitBreaker(messageSender, 1, 20*time.Millisecond, 1)
+
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	messageSender.On("Send", ctx, message).Return("", providerDown)
+
+	// Act
+	_, _ = breaker.Send(ctx, message)
+	time.Sleep(30 * time.Millisecond)
+	_, probeErr := breaker.Send(ctx, message)
+
+	// Assert
+	assert.Equal(t, providerDown, probeErr)
+	status := breaker.Status()
+	assert.Equal(t, domain.BreakerOpen, status.State)
+	assert.Equal(t, int64(2), status.Trips)
+}
+
+func TestCircuitBreaker_CountsOnlyProviderFailures(t *testing.T) {
+	cases := map[string]struct {
+		err   error
+		trips bool
+	}{
+		"provider 5xx":    {err: &domain.ProviderError{StatusCode: 502}, trips: true},
+		"timeout":         {err: fmt.Errorf("failed to send request: %w", context.DeadlineExceeded), trips: true},
+		"network":         {err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, trips: true},
+		"rejected by 4xx": {err: &domain.ProviderError{StatusCode: 400}},
+		"bad message":     {err: fmt.Errorf("%w: empty content", domain.ErrInvalidMessage)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			messageSender := new(mockedMessageSender)
+			breaker := services.NewCircuitBreaker(messageSender, 2, time.Minute, 1)
+
+			message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+			messageSender.On("Send", ctx, message).Return("", tc.err)
+
+			// Act
+			_, _ = breaker.Send(ctx, message)
+			_, _ = breaker.Send(ctx, message)
+
+			// Assert
+			if tc.trips {
+				assert.Equal(t, domain.BreakerOpen, breaker.Status().State)
+			} else {
+				assert.Equal(t, domain.BreakerClosed, breaker.Status().State)
+				assert.Equal(t, 0, breaker.Status().ConsecutiveFailures)
+			}
+		})
+	}
+}
+
+func TestTenantCircuitBreaker_IsolatesTenants(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	breaker := services.NewTenantCircuitBreaker(messageSender, 1, time.Minute, 1)
+
+	broken := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1", TenantID: "acme"}
+	healthy := domain.Message{ID: 2, To: "+905551111002", Content: "Test message 2", TenantID: "globex"}
+	untenanted := domain.Message{ID: 3, To: "+905551111003", Content: "Test message 3"}
+	messageSender.On("Send", ctx, broken).Return("", providerDown).Once()
+	messageSender.On("Send", ctx, healthy).Return("msg-id-2", nil)
+	messageSender.On("Send", ctx, untenanted).Return("msg-id-3", nil)
+
+	// Act
+	_, _ = breaker.Send(ctx, broken)
+	_, rejectedErr := breaker.Send(ctx, broken)
+	_, healthyErr := breaker.Send(ctx, healthy)
+	_, untenantedErr := breaker.Send(ctx, untenanted)
+
+	// Assert
+	assert.ErrorIs(t, rejectedErr, domain.ErrCircuitOpen)
+	assert.NoError(t, healthyErr)
+	assert.NoError(t, untenantedErr)
+
+	status := breaker.Status()
+	assert.Equal(t, domain.BreakerClosed, status.State)
+	assert.Equal(t, domain.BreakerOpen, status.Tenants["acme"].State)
+	assert.Equal(t, int64(1), status.Tenants["acme"].Rejected)
+	assert.Equal(t, domain.BreakerClosed, status.Tenants["globex"].State)
+}
+
+func TestCircuitBreaker_OpenBreakerSparesRateLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	rateLimiter := new(mockedRateLimiter)
+	breaker := services.NewCircuitBreaker(services.NewRateLimitedSender(messageSender, rateLimiter), 1, time.Minute, 1)
+
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	rateLimiter.On("Reserve", ctx, message.To).Return(time.Duration(0), nil).Once()
+	messageSender.On("Send", ctx, message).Return("", providerDown).Once()
+
+	// Act
+	_, _ = breaker.Send(ctx, message)
+	_, rejectedErr := breaker.Send(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, rejectedErr, domain.ErrCircuitOpen)
+	rateLimiter.AssertNumberOfCalls(t, "Reserve", 1)
+}
+
+func TestCircuitBreaker_DeferredSendIsNotAProbe(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageSender := new(mockedMessageSender)
+	rateLimiter := new(mockedRateLimiter)
+	breaker := services.NewCircuitBreaker(services.NewRateLimitedSender(messageSender, rateLimiter),
+		1, 20*time.Millisecond, 1)
+
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	rateLimiter.On("Reserve", ctx, message.To).Return(time.Duration(0), nil).Once()
+	rateLimiter.On("Reserve", ctx, message.To).Return(time.Second, nil).Once()
+	messageSender.On("Send", ctx, message).Return("", providerDown).Once()
+
+	// Act
+	_, _ = breaker.Send(ctx, message)
+	time.Sleep(30 * time.Millisecond)
+	_, deferredErr := breaker.Send(ctx, message)
+
+	// Assert
+	var deferred *domain.DeferredError
+	assert.ErrorAs(t, deferredErr, &deferred)
+	assert.Equal(t, domain.BreakerHalfOpen, breaker.Status().State)
+	assert.Equal(t, 1, breaker.Status().ConsecutiveFailures)
+}
@@ -0,0 +1,26 @@
+package circuit_breaker
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type mockedMessageSender struct {
+	mock.Mock
+}
+
+func (s *mockedMessageSender) Send(ctx context.Context, message domain.Message) (string, error) {
+	args := s.Called(ctx, message)
+	return args.String(0), args.Error(1)
+}
+
+type mockedRateLimiter struct {
+	mock.Mock
+}
+
+func (l *mockedRateLimiter) Reserve(ctx context.Context, recipient string) (time.Duration, error) {
+	args := l.Called(ctx, recipient)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
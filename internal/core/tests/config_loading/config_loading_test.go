@@ -0,0 +1,203 @@
+package config_loading
+
+import (
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const baseYAML = `
+app:
+  webhook_url: "https://provider.example/send"
+  send_interval_seconds: 120
+  max_retries: 3
+suppression:
+  opt_out_keywords: ["STOP"]
+database:
+  host: "localhost"
+  port: 5432
+  user: "messenger"
+  password: "from-file"
+  name: "messenger_db"
+redis:
+  url: "redis://localhost:6379"
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_EnvOverridesEveryField(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.yaml", baseYAML)
+	t.Setenv("MESSENGER_APP_SEND_INTERVAL_SECONDS", "5")
+	t.Setenv("MESSENGER_APP_MESSAGE_CHAR_LIMIT", "320")
+	t.Setenv("MESSENGER_MULTIPART_ENABLED", "true")
+	t.Setenv("MESSENGER_SUPPRESSION_OPT_OUT_KEYWORDS", "STOP,END")
+	t.Setenv("MESSENGER_DATABASE_HOST", "db.internal")
+	t.Setenv("PGHOST", "ignored.internal")
+	t.Setenv("PGUSER", "legacy-user")
+
+	// Act
+	cfg, err := config.Load(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 5, cfg.App.SendIntervalSecs)
+	assert.Equal(t, 320, cfg.App.MessageCharLimit)
+	assert.True(t, cfg.Multipart.Enabled)
+	assert.Equal(t, []string{"STOP", "END"}, cfg.Suppression.OptOutKeywords)
+	// The prefixed variable wins over the legacy one, which still works on its own
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "legacy-user", cfg.Database.User)
+}
+
+func TestLoad_SecretFromFile(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.yaml", baseYAML)
+	t.Setenv("MESSENGER_DATABASE_PASSWORD_FILE", writeFile(t, "pg_password", "s3cret\n"))
+	t.Setenv("WEBHOOK_KEY_FILE", writeFile(t, "webhook_key", "key-123"))
+
+	// Act
+	cfg, err := config.Load(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	assert.Equal(t, "key-123", cfg.App.WebhookKey)
+}
+
+func TestLoad_MissingSecretFile(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.yaml", baseYAML)
+	t.Setenv("MESSENGER_DATABASE_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	// Act
+	_, err := config.Load(path)
+
+	// Assert
+	assert.ErrorContains(t, err, "MESSENGER_DATABASE_PASSWORD_FILE")
+}
+
+func TestLoad_InvalidNumberFromEnv(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.yaml", baseYAML)
+	t.Setenv("MESSENGER_APP_MAX_RETRIES", "three")
+
+	// Act
+	_, err := config.Load(path)
+
+	// Assert
+	assert.ErrorContains(t, err, "max_retries")
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	// Arrange
+	cfg, err := config.Load(writeFile(t, "config.yaml", baseYAML))
+	assert.NoError(t, err)
+	cfg.App.SendIntervalSecs = 0
+	cfg.Dedupe.Mode = "drop"
+	cfg.Dedupe.WindowSeconds = 60
+	cfg.Database.Host = ""
+	cfg.Redis.URL = "http://localhost:6379"
+
+	// Act
+	err = cfg.Validate()
+
+	// Assert
+	var validation *config.ValidationError
+	assert.ErrorAs(t, err, &validation)
+	assert.Equal(t, []string{
+		"app.send_interval_seconds must be at least 1, got 0",
+		`dedupe.mode must be one of reject, collapse, got "drop"`,
+		"database.host is required (env MESSENGER_DATABASE_HOST)",
+		"redis.url must use redis or rediss",
+	}, validation.Problems)
+}
+
+func TestValidate_ShippedConfig(t *testing.T) {
+	// Act
+	cfg, err := config.Load("../../../../config/config.yaml")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NoError(t, cfg.Validate())
+}
+
+const tenantYAML = baseYAML + `
+tenants:
+  marketing:
+    message_char_limit: 160
+    max_retries: 5
+  billing-team:
+    webhook_url: "https://billing.example/send"
+`
+
+func TestLoad_TenantSettingsFromEnv(t *testing.T) {
+	// Arrange
+	path := writeFile(t, "config.yaml", tenantYAML)
+	t.Setenv("MESSENGER_TENANTS_MARKETING_MAX_RETRIES", "7")
+	t.Setenv("MESSENGER_TENANTS_BILLING_TEAM_WEBHOOK_KEY_FILE", writeFile(t, "billing_key", "billing-key\n"))
+
+	// Act
+	cfg, err := config.Load(path)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 7, cfg.Tenants["marketing"].MaxRetries)
+	assert.Equal(t, 160, cfg.Tenants["marketing"].MessageCharLimit)
+	assert.Equal(t, "billing-key", cfg.Tenants["billing-team"].WebhookKey)
+	assert.Equal(t, "https://billing.example/send", cfg.Tenants["billing-team"].WebhookURL)
+}
+
+func TestTenantSettings_InheritAppSettings(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.App.WebhookURL = "https://provider.example/send"
+	cfg.App.WebhookKey = "app-key"
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+	cfg.Tenants = map[string]config.Tenant{
+		"marketing": {MaxRetries: 5},
+		"billing":   {WebhookURL: "https://billing.example/send"},
+	}
+
+	// Act
+	marketing := cfg.TenantSettings("marketing")
+	billing := cfg.TenantSettings("billing")
+	unknown := cfg.TenantSettings("")
+
+	// Assert
+	assert.Equal(t, config.Tenant{WebhookURL: "https://provider.example/send", WebhookKey: "app-key",
+		MessageCharLimit: 1000, MaxRetries: 5}, marketing)
+	// The app-wide key isn't sent to a tenant's own webhook
+	assert.Equal(t, config.Tenant{WebhookURL: "https://billing.example/send",
+		MessageCharLimit: 1000, MaxRetries: 3}, billing)
+	assert.Equal(t, config.Tenant{WebhookURL: "https://provider.example/send", WebhookKey: "app-key",
+		MessageCharLimit: 1000, MaxRetries: 3}, unknown)
+	assert.Equal(t, 5, cfg.MaxRetriesCeiling())
+}
+
+func TestValidate_Tenants(t *testing.T) {
+	// Arrange
+	cfg, err := config.Load(writeFile(t, "config.yaml", baseYAML))
+	assert.NoError(t, err)
+	cfg.Tenants = map[string]config.Tenant{
+		"Marketing": {},
+		"billing":   {WebhookURL: "ftp://billing.example", MaxRetries: -1},
+	}
+
+	// Act
+	err = cfg.Validate()
+
+	// Assert
+	var invalid *config.ValidationError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Len(t, invalid.Problems, 3)
+}
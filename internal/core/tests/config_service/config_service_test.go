@@ -0,0 +1,97 @@
+package config_service
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func baseConfig() config.Config {
+	var cfg config.Config
+	cfg.App.SendIntervalSecs = 120
+	cfg.App.BatchSize = 2
+	cfg.App.MaxRetries = 3
+	cfg.App.WebhookURL = "https://provider.example/send"
+	cfg.Logging.Level = "info"
+	cfg.Database.Host = "localhost"
+	cfg.Database.Port = 5432
+	cfg.Database.User = "messenger"
+	cfg.Database.Name = "messenger_db"
+	cfg.Redis.URL = "redis://localhost:6379"
+	return cfg
+}
+
+func TestReload_AppliesOnlyReloadableSettings(t *testing.T) {
+	// Arrange
+	loaded := baseConfig()
+	loaded.App.SendIntervalSecs = 30
+	loaded.App.BatchSize = 20
+	loaded.App.WebhookURL = "https://other.example/send"
+	loaded.Redis.URL = "redis://cache:6379"
+
+	service := services.NewConfigService(baseConfig(), func() (config.Config, error) { return loaded, nil })
+
+	// Act
+	result, err := service.Reload(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app.send_interval_seconds", "app.batch_size"}, result.Applied)
+	assert.Equal(t, []string{"app", "redis"}, result.RestartRequired)
+
+	current := service.Current()
+	assert.Equal(t, 30, current.App.SendIntervalSecs)
+	assert.Equal(t, 20, current.App.BatchSize)
+	assert.Equal(t, "https://provider.example/send", current.App.WebhookURL)
+	assert.Equal(t, "redis://localhost:6379", current.Redis.URL)
+}
+
+func TestReload_AppliesTenants(t *testing.T) {
+	// Arrange
+	loaded := baseConfig()
+	loaded.Tenants = map[string]config.Tenant{"marketing": {MaxRetries: 5}}
+
+	service := services.NewConfigService(baseConfig(), func() (config.Config, error) { return loaded, nil })
+
+	// Act
+	result, err := service.Reload(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tenants"}, result.Applied)
+	assert.Empty(t, result.RestartRequired)
+	assert.True(t, service.Current().HasTenant("marketing"))
+}
+
+func TestReload_InvalidConfigKeepsCurrent(t *testing.T) {
+	// Arrange
+	loaded := baseConfig()
+	loaded.App.SendIntervalSecs = 0
+
+	service := services.NewConfigService(baseConfig(), func() (config.Config, error) { return loaded, nil })
+
+	// Act
+	_, err := service.Reload(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidConfig)
+	assert.Equal(t, 120, service.Current().App.SendIntervalSecs)
+}
+
+func TestReload_ReadErrorKeepsCurrent(t *testing.T) {
+	// Arrange
+	readErr := errors.New("config read error: file not found")
+	service := services.NewConfigService(baseConfig(), func() (config.Config, error) { return config.Config{}, readErr })
+
+	// Act
+	_, err := service.Reload(context.Background())
+
+	// Assert
+	assert.ErrorIs(t, err, readErr)
+	assert.NotErrorIs(t, err, domain.ErrInvalidConfig)
+	assert.Equal(t, baseConfig(), service.Current())
+}
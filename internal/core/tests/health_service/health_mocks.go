@@ -0,0 +1,30 @@
+package health_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockedHealthCheck struct {
+	mock.Mock
+	name string
+}
+
+func (c *mockedHealthCheck) Name() string {
+	return c.name
+}
+
+func (c *mockedHealthCheck) Check(ctx context.Context) error {
+	args := c.Called(ctx)
+	return args.Error(0)
+}
+
+type mockedSenderMonitor struct {
+	mock.Mock
+}
+
+func (m *mockedSenderMonitor) SenderStatus() domain.SenderStatus {
+	args := m.Called()
+	return args.Get(0).(domain.SenderStatus)
+}
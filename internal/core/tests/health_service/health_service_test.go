@@ -0,0 +1,84 @@
+package health_service
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/ports"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestReadiness_DependencyDown(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	postgres := &mockedHealthCheck{name: "postgres"}
+	redis := &mockedHealthCheck{name: "redis"}
+	postgres.On("Check", mock.Anything).Return(nil)
+	redis.On("Check", mock.Anything).Return(errors.New("connection refused"))
+
+	service := services.NewHealthService(nil, []ports.HealthCheck{postgres, redis})
+
+	// Act
+	report := service.Readiness(ctx)
+
+	// Assert
+	assert.Equal(t, domain.HealthDown, report.Status)
+	assert.Equal(t, domain.HealthUp, report.Checks["postgres"].Status)
+	assert.Equal(t, domain.HealthDown, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+}
+
+func TestLiveness_IgnoresDependencies(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	monitor := new(mockedSenderMonitor)
+	postgres := &mockedHealthCheck{name: "postgres"}
+	monitor.On("SenderStatus").Return(domain.SenderStatus{Running: false})
+
+	service := services.NewHealthService([]ports.HealthCheck{services.NewSenderHealthCheck(monitor)},
+		[]ports.HealthCheck{postgres})
+
+	// Act
+	report := service.Liveness(ctx)
+
+	// Assert
+	assert.Equal(t, domain.HealthUp, report.Status)
+	assert.Contains(t, report.Checks, "auto_sender")
+	postgres.AssertNotCalled(t, "Check", mock.Anything)
+}
+
+func TestSenderHealthCheck(t *testing.T) {
+	recent := time.Now().Add(-10 * time.Second)
+	stale := time.Now().Add(-2 * time.Minute)
+
+	cases := map[string]struct {
+		status  domain.SenderStatus
+		healthy bool
+	}{
+		"stopped":        {domain.SenderStatus{Running: false}, true},
+		"ticking":        {domain.SenderStatus{Running: true, IntervalSeconds: 5, LastTickAt: &recent}, true},
+		"stalled":        {domain.SenderStatus{Running: true, IntervalSeconds: 5, LastTickAt: &stale}, false},
+		"slow but due":   {domain.SenderStatus{Running: true, IntervalSeconds: 60, LastTickAt: &stale}, true},
+		"first tick due": {domain.SenderStatus{Running: true, IntervalSeconds: 5, StartedAt: &recent}, true},
+		"never ticked":   {domain.SenderStatus{Running: true, IntervalSeconds: 5, StartedAt: &stale}, false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			monitor := new(mockedSenderMonitor)
+			monitor.On("SenderStatus").Return(tc.status)
+			check := services.NewSenderHealthCheck(monitor)
+
+			// Act
+			err := check.Check(context.Background())
+
+			// Assert
+			assert.Equal(t, tc.healthy, err == nil, "error: %v", err)
+		})
+	}
+}
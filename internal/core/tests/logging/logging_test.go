@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/logging"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestMaskPhone(t *testing.T) {
+	cases := map[string]string{
+		"+905551111001": "+90********01",
+		"+4915":         "*****",
+		"":              "",
+	}
+
+	for number, expected := range cases {
+		// Act & Assert
+		assert.Equal(t, expected, logging.MaskPhone(number))
+	}
+}
+
+func TestSetup_InvalidConfig(t *testing.T) {
+	// Act & Assert
+	assert.Error(t, logging.Setup(&bytes.Buffer{}, "loud", "json"))
+	assert.Error(t, logging.Setup(&bytes.Buffer{}, "info", "xml"))
+}
+
+func TestForMessage_JSONLineWithContext(t *testing.T) {
+	// Arrange
+	var buf bytes.Buffer
+	assert.NoError(t, logging.Setup(&buf, "info", "json"))
+	defer slog.SetDefault(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+
+	ctx := logging.WithRequestID(context.Background(), "req-123")
+	msg := domain.Message{ID: 42, To: "+905551111001", RetryCount: 1}
+
+	// Act
+	logging.ForMessage(msg).InfoContext(ctx, "Message sent")
+	logging.ForMessage(msg).DebugContext(ctx, "Below the configured level")
+
+	// Assert
+	var line map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "Message sent", line["msg"])
+	assert.Equal(t, float64(42), line["message_id"])
+	assert.Equal(t, "+90********01", line["recipient"])
+	assert.Equal(t, float64(2), line["attempt"])
+	assert.Equal(t, "req-123", line["request_id"])
+}
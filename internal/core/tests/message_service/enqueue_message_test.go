@@ -0,0 +1,492 @@
+package message_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/trace"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnqueueMessage_Success(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithStatusNotifier(new(mockedStatusNotifier)))
+
+	message := &domain.Message{To: " +905551111001 ", Content: "Test message 1",
+		CallbackURL: "https://example.com/hooks/sms"}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "+905551111001", message.To)
+	assert.Equal(t, domain.StatusPending, message.Status)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestEnqueueMessage_CallbackURLWithoutCallbacksEnabled(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender))
+
+	message := &domain.Message{To: "+905551111001", Content: "Test message 1",
+		CallbackURL: "https://example.com/hooks/sms"}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_InvalidCallbackURL(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithStatusNotifier(new(mockedStatusNotifier)))
+
+	message := &domain.Message{To: "+905551111001", Content: "Test message 1", CallbackURL: "ftp://example.com"}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_RejectsInternalCallbackURL(t *testing.T) {
+	for _, callbackURL := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"https://[::1]/hook",
+		"http://metadata.google.internal/computeMetadata/v1",
+	} {
+		t.Run(callbackURL, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			messageRepo := new(mockedMessageRepo)
+			service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+				services.WithStatusNotifier(new(mockedStatusNotifier)))
+
+			message := &domain.Message{To: "+905551111001", Content: "Test message 1", CallbackURL: callbackURL}
+
+			// Act
+			err := service.EnqueueMessage(ctx, message)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+			messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSendMessage_NotifiesStatusChange(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	statusNotifier := new(mockedStatusNotifier)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithStatusNotifier(statusNotifier))
+
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1",
+		Status: domain.StatusPending, CallbackURL: "https://example.com/hooks/sms"}
+
+	messageSender.On("Send", anyCtx, message).Return("msg-12345", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	statusNotifier.On("Notify", anyCtx, withStatus(1, domain.StatusSent)).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	err := service.SendMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	statusNotifier.AssertExpectations(t)
+}
+
+func TestEnqueueMessage_RecordsEncoding(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithContentLimits(160, 1))
+
+	message := &domain.Message{To: "+905551111001", Content: "Merhaba, şifreniz 1234"}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "gsm7-turkish", message.Encoding)
+	assert.Equal(t, 1, message.Segments)
+}
+
+func TestEnqueueMessage_OverSegmentLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithContentLimits(160, 1))
+
+	// 71 UCS-2 characters need two segments
+	message := &domain.Message{To: "+905551111001", Content: strings.Repeat("ж", 71)}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	assert.Contains(t, err.Error(), "2 ucs2 segments")
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_Transliterates(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{To: "+905551111001", Content: "Şifreniz 1234", Transliterate: true}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Sifreniz 1234", message.Content)
+	assert.Equal(t, "gsm7", message.Encoding)
+}
+
+func TestPreviewTransliteration(t *testing.T) {
+	// Arrange
+	service := services.NewMessageService(new(mockedMessageRepo), new(mockedCacheService), new(mockedMessageSender))
+
+	// 80 Turkish characters need the shift table and two segments, plain ASCII fits in one
+	content := strings.Repeat("ş", 80)
+
+	// Act
+	preview := service.PreviewTransliteration(content)
+
+	// Assert
+	assert.Equal(t, "gsm7-turkish", preview.Original.Encoding)
+	assert.Equal(t, 2, preview.Original.Segments)
+	assert.Equal(t, strings.Repeat("s", 80), preview.Transliterated.Content)
+	assert.Equal(t, 1, preview.Transliterated.Segments)
+	assert.Equal(t, 1, preview.SegmentsSaved)
+}
+
+func TestEnqueueMessage_NormalizesRecipient(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithDefaultRegion("TR"))
+
+	message := &domain.Message{To: "0555 111 10 01", Content: "Test message 1"}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "+905551111001", message.To)
+	assert.Equal(t, "TR", message.CountryCode)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestEnqueueMessage_InvalidRecipient(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithDefaultRegion("TR"))
+
+	message := &domain.Message{To: "0555 111 10", Content: "Test message 1"}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_RendersTemplate(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	templates := new(mockedTemplateRenderer)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithTemplates(templates), services.WithContentLimits(20, 0))
+
+	vars := map[string]string{"code": "1234"}
+	message := &domain.Message{To: "+905551111001", TemplateID: 7, Variables: vars}
+
+	templates.On("Render", anyCtx, uint(7), 0, "", vars).
+		Return(domain.RenderedTemplate{Content: "Your code is 1234", Version: 3, Locale: "en"}, nil)
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Your code is 1234", message.Content)
+	assert.Equal(t, 3, message.TemplateVersion)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestEnqueueMessage_RenderedTemplateOverLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	templates := new(mockedTemplateRenderer)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithTemplates(templates), services.WithContentLimits(10, 0))
+
+	vars := map[string]string{"code": "1234"}
+	message := &domain.Message{To: "+905551111001", TemplateID: 7, Variables: vars}
+
+	templates.On("Render", anyCtx, uint(7), 0, "", vars).
+		Return(domain.RenderedTemplate{Content: "Your code is 1234", Version: 3, Locale: "en"}, nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_UsesRecipientLocale(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	templates := new(mockedTemplateRenderer)
+	recipients := new(mockedRecipientRepo)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithTemplates(templates), services.WithRecipients(recipients))
+
+	vars := map[string]string{"code": "1234"}
+	message := &domain.Message{To: "+994501234567", TemplateID: 7, Variables: vars}
+
+	recipients.On("GetRecipient", anyCtx, "+994501234567").Return(&domain.Recipient{Phone: "+994501234567", Locale: "az"}, nil)
+	templates.On("Render", anyCtx, uint(7), 0, "az", vars).
+		Return(domain.RenderedTemplate{Content: "Kodunuz 1234", Version: 2, Locale: "tr"}, nil)
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "tr", message.Locale)
+	assert.Equal(t, "Kodunuz 1234", message.Content)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestEnqueueMessage_RequestedLocaleOverridesPreference(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	templates := new(mockedTemplateRenderer)
+	recipients := new(mockedRecipientRepo)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithTemplates(templates), services.WithRecipients(recipients))
+
+	vars := map[string]string{"code": "1234"}
+	message := &domain.Message{To: "+994501234567", TemplateID: 7, Variables: vars,
+		Locale: "DE_de", Timezone: "Europe/Berlin"}
+
+	templates.On("Render", anyCtx, uint(7), 0, "de-de", vars).
+		Return(domain.RenderedTemplate{Content: "Ihr Code 1234", Version: 2, Locale: "de"}, nil)
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "de", message.Locale)
+	recipients.AssertNotCalled(t, "GetRecipient", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_TimezoneFromCountry(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{To: "+994501234567", Content: "Test message 1", Category: " Marketing "}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Baku", message.Timezone)
+	assert.Equal(t, "marketing", message.Category)
+}
+
+func TestEnqueueMessage_InvalidTimezone(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{To: "+905551111001", Content: "Test message 1", Timezone: "Mars/Olympus_Mons"}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_DuplicateRejected(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithDedupe(services.DedupeReject, time.Minute))
+
+	message := &domain.Message{To: "+905551111001", Content: "Test message 1"}
+
+	cacheService.On("SetIfAbsent", anyCtx, mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "dedupe:")
+	}), "claiming", time.Minute).Return(false, nil)
+	cacheService.On("Get", anyCtx, mock.AnythingOfType("string")).Return("5", nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	var duplicate *domain.DuplicateError
+	assert.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, uint(5), duplicate.OriginalID)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_DedupeRecordsOwner(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithDedupe(services.DedupeReject, time.Minute))
+
+	message := &domain.Message{To: "+905551111001", Content: "Test message 1"}
+
+	cacheService.On("SetIfAbsent", anyCtx, mock.AnythingOfType("string"), "claiming", time.Minute).Return(true, nil)
+	messageRepo.On("CreateMessage", anyCtx, message).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Message).ID = 7
+	}).Return(nil)
+	cacheService.On("SetWithTTL", anyCtx, mock.AnythingOfType("string"), "7", time.Minute).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.NotEmpty(t, message.DedupeHash)
+	cacheService.AssertCalled(t, "SetWithTTL", anyCtx, "dedupe:"+message.DedupeHash, "7", time.Minute)
+}
+
+func TestEnqueueMessage_RecordsTraceParent(t *testing.T) {
+	// Arrange
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{To: "+905551111001", Content: "Test message 1"}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", message.TraceParent)
+}
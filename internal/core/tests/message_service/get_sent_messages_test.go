@@ -31,10 +31,10 @@ func TestGetSentMessages_Success(t *testing.T) {
 	}
 
 	// Set up expectations
-	messageRepo.On("GetSentMessages", ctx).Return(messages, nil)
+	messageRepo.On("GetSentMessages", anyCtx, "").Return(messages, nil)
 
 	//Act
-	result, err := service.GetSentMessages(ctx)
+	result, err := service.GetSentMessages(ctx, "")
 
 	// Assert
 	assert.NoError(t, err)
@@ -52,10 +52,10 @@ func TestGetSentMessages_Error(t *testing.T) {
 	service := services.NewMessageService(messageRepo, cacheService, messageSender)
 
 	expectedError := errors.New("database error")
-	messageRepo.On("GetSentMessages", ctx).Return([]domain.Message{}, expectedError)
+	messageRepo.On("GetSentMessages", anyCtx, "").Return([]domain.Message{}, expectedError)
 
 	// Act
-	result, err := service.GetSentMessages(ctx)
+	result, err := service.GetSentMessages(ctx, "")
 
 	// Assert
 	assert.Error(t, err)
@@ -2,8 +2,10 @@ package message_service
 
 import (
 	"context"
+	"github.com/hasElvin/messenger-svc/config"
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type mockedMessageRepo struct {
@@ -18,22 +20,44 @@ type mockedMessageSender struct {
 	mock.Mock
 }
 
+type mockedStatusNotifier struct {
+	mock.Mock
+}
+
 func (r *mockedMessageRepo) GetPendingMessages(ctx context.Context,
-	limit, messageCharLimit, maxRetries int) ([]domain.Message, error) {
+	limit, maxRetries int) ([]domain.Message, error) {
 
-	args := r.Called(ctx, limit, messageCharLimit, maxRetries)
+	args := r.Called(ctx, limit, maxRetries)
 	return args.Get(0).([]domain.Message), args.Error(1)
 }
 
 func (r *mockedMessageRepo) UpdateMessageStatus(ctx context.Context,
-	id uint, status string) error {
+	msg domain.Message, from domain.Status) error {
 
-	args := r.Called(ctx, id, status)
+	args := r.Called(ctx, msg, from)
 	return args.Error(0)
 }
 
-func (r *mockedMessageRepo) GetSentMessages(ctx context.Context) ([]domain.Message, error) {
-	args := r.Called(ctx)
+func (r *mockedMessageRepo) GetMessageByProviderID(ctx context.Context,
+	providerMessageID string) (*domain.Message, error) {
+
+	args := r.Called(ctx, providerMessageID)
+	if msg, ok := args.Get(0).(*domain.Message); ok {
+		return msg, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (r *mockedMessageRepo) GetMessage(ctx context.Context, id uint) (*domain.Message, error) {
+	args := r.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Message), args.Error(1)
+}
+
+func (r *mockedMessageRepo) GetSentMessages(ctx context.Context, tenantID string) ([]domain.Message, error) {
+	args := r.Called(ctx, tenantID)
 	return args.Get(0).([]domain.Message), args.Error(1)
 }
 
@@ -42,11 +66,21 @@ func (r *mockedMessageRepo) CreateMessage(ctx context.Context, message *domain.M
 	return args.Error(0)
 }
 
+func (r *mockedMessageRepo) CountPendingMessages(ctx context.Context, maxRetries int) (int64, error) {
+	args := r.Called(ctx, maxRetries)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (r *mockedMessageRepo) IncrementRetryCount(ctx context.Context, id uint) error {
 	args := r.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (r *mockedMessageRepo) DeferMessage(ctx context.Context, id uint, until time.Time) error {
+	args := r.Called(ctx, id, until)
+	return args.Error(0)
+}
+
 func (r *mockedMessageRepo) SeedSampleMessages() error {
 	args := r.Called()
 	return args.Error(0)
@@ -62,12 +96,113 @@ func (c *mockedCacheService) Set(ctx context.Context, key, value string) error {
 	return args.Error(0)
 }
 
+func (c *mockedCacheService) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	args := c.Called(ctx, key, value, ttl)
+	return args.Error(0)
+}
+
+func (c *mockedCacheService) SetIfAbsent(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	args := c.Called(ctx, key, value, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
 func (c *mockedCacheService) Get(ctx context.Context, key string) (string, error) {
 	args := c.Called(ctx, key)
 	return args.String(0), args.Error(1)
 }
 
+func (c *mockedCacheService) Delete(ctx context.Context, key string) error {
+	args := c.Called(ctx, key)
+	return args.Error(0)
+}
+
 func (s *mockedMessageSender) Send(ctx context.Context, message domain.Message) (string, error) {
 	args := s.Called(ctx, message)
 	return args.String(0), args.Error(1)
 }
+
+func (n *mockedStatusNotifier) Notify(ctx context.Context, message domain.Message) error {
+	args := n.Called(ctx, message)
+	return args.Error(0)
+}
+
+// withStatus matches the message passed to UpdateMessageStatus by ID and new status
+func withStatus(id uint, status domain.Status) interface{} {
+	return mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == id && msg.Status == status
+	})
+}
+
+type mockedTemplateRenderer struct {
+	mock.Mock
+}
+
+func (r *mockedTemplateRenderer) Render(ctx context.Context, id uint, version int, locale string,
+	vars map[string]string) (domain.RenderedTemplate, error) {
+
+	args := r.Called(ctx, id, version, locale, vars)
+	return args.Get(0).(domain.RenderedTemplate), args.Error(1)
+}
+
+type mockedRecipientRepo struct {
+	mock.Mock
+}
+
+func (r *mockedRecipientRepo) GetRecipient(ctx context.Context, phone string) (*domain.Recipient, error) {
+	args := r.Called(ctx, phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Recipient), args.Error(1)
+}
+
+func (r *mockedRecipientRepo) UpsertRecipient(ctx context.Context, recipient *domain.Recipient) error {
+	args := r.Called(ctx, recipient)
+	return args.Error(0)
+}
+
+type mockedSuppressionChecker struct {
+	mock.Mock
+}
+
+func (s *mockedSuppressionChecker) IsSuppressed(ctx context.Context, phone string) (bool, error) {
+	args := s.Called(ctx, phone)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockedDispatchMetrics struct {
+	mock.Mock
+}
+
+func (m *mockedDispatchMetrics) MessageSent() {
+	m.Called()
+}
+
+func (m *mockedDispatchMetrics) MessageFailed(err error) {
+	m.Called(err)
+}
+
+func (m *mockedDispatchMetrics) MessageRetried(err error) {
+	m.Called(err)
+}
+
+func (m *mockedDispatchMetrics) ObserveTick(duration time.Duration) {
+	m.Called(duration)
+}
+
+func (m *mockedDispatchMetrics) SetSenderRunning(running bool) {
+	m.Called(running)
+}
+
+// anyCtx matches any context, since the service hands collaborators contexts derived
+// from the caller's to carry tracing spans
+var anyCtx = mock.MatchedBy(func(context.Context) bool { return true })
+
+type mockedConfigProvider struct {
+	mock.Mock
+}
+
+func (m *mockedConfigProvider) Current() config.Config {
+	args := m.Called()
+	return args.Get(0).(config.Config)
+}
@@ -0,0 +1,218 @@
+package message_service
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+func TestEnqueueMessage_SplitsWithSuffix(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithContentLimits(0, 5), services.WithMultipart(services.MultipartSuffix))
+
+	message := &domain.Message{To: "+905551111001", Content: strings.Repeat("a", 300)}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, message.Parts, 2)
+	assert.Equal(t, 2, message.PartTotal)
+	for i, part := range message.Parts {
+		assert.Equal(t, i+1, part.PartNumber)
+		assert.Equal(t, 1, part.Segments)
+		assert.Equal(t, domain.StatusPending, part.Status)
+		assert.Zero(t, part.ConcatRef)
+	}
+	assert.True(t, strings.HasSuffix(message.Parts[0].Content, " (1/2)"))
+	assert.True(t, strings.HasSuffix(message.Parts[1].Content, " (2/2)"))
+	assert.LessOrEqual(t, len(message.Parts[0].Content), 160)
+}
+
+func TestEnqueueMessage_MultipartSplitsOverCharLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+		services.WithContentLimits(15, 3), services.WithMultipart(services.MultipartUDH))
+
+	message := &domain.Message{To: "+905551111001", Content: strings.Repeat("a", 300)}
+	tooLong := &domain.Message{To: "+905551111001", Content: strings.Repeat("a", 500)}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+	tooLongErr := service.EnqueueMessage(ctx, tooLong)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, message.Parts, 2)
+
+	// The segment limit still caps how long a message can get
+	assert.ErrorIs(t, tooLongErr, domain.ErrInvalidMessage)
+	messageRepo.AssertNumberOfCalls(t, "CreateMessage", 1)
+}
+
+func TestEnqueueMessage_SplitsWithUDH(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithContentLimits(0, 5), services.WithMultipart(services.MultipartUDH))
+
+	message := &domain.Message{To: "+905551111001", Content: strings.Repeat("a", 306)}
+
+	messageRepo.On("CreateMessage", anyCtx, message).Return(nil)
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, message.Parts, 2)
+	assert.Equal(t, strings.Repeat("a", 153), message.Parts[0].Content)
+	assert.NotZero(t, message.Parts[0].ConcatRef)
+	assert.Equal(t, message.ConcatRef, message.Parts[1].ConcatRef)
+}
+
+func TestSendPendingMessages_MultipartResumesInOrder(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MaxRetries = 3
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Part 1 went out on an earlier tick, part 2 fails now, part 3 must wait
+	parentID := uint(1)
+	parts := []domain.Message{
+		{ID: 2, ParentID: &parentID, To: "+905551111001", Content: "a (1/3)", Status: domain.StatusSent,
+			PartNumber: 1, PartTotal: 3, ProviderMessageID: "msg-id-2"},
+		{ID: 3, ParentID: &parentID, To: "+905551111001", Content: "b (2/3)", Status: domain.StatusPending,
+			PartNumber: 2, PartTotal: 3},
+		{ID: 4, ParentID: &parentID, To: "+905551111001", Content: "c (3/3)", Status: domain.StatusPending,
+			PartNumber: 3, PartTotal: 3},
+	}
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "abc", Status: domain.StatusPending, PartTotal: 3, Parts: parts},
+	}
+
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, parts[1]).Return("", errors.New("send error"))
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(1)).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, parts[0])
+	messageSender.AssertNotCalled(t, "Send", anyCtx, parts[2])
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendPendingMessages_MultipartFailureFailsUnsentParts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MaxRetries = 3
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Part 1 went out on an earlier tick, part 2 fails on the last allowed attempt
+	parentID := uint(1)
+	parts := []domain.Message{
+		{ID: 2, ParentID: &parentID, To: "+905551111001", Content: "a (1/3)", Status: domain.StatusSent,
+			PartNumber: 1, PartTotal: 3, ProviderMessageID: "msg-id-2"},
+		{ID: 3, ParentID: &parentID, To: "+905551111001", Content: "b (2/3)", Status: domain.StatusPending,
+			PartNumber: 2, PartTotal: 3},
+		{ID: 4, ParentID: &parentID, To: "+905551111001", Content: "c (3/3)", Status: domain.StatusPending,
+			PartNumber: 3, PartTotal: 3},
+	}
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "abc", Status: domain.StatusPending, PartTotal: 3, Parts: parts,
+			RetryCount: 2},
+	}
+
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, parts[1]).Return("", errors.New("send error"))
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(1)).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusFailed), domain.StatusPending).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(3, domain.StatusFailed), domain.StatusPending).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(4, domain.StatusFailed), domain.StatusPending).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageRepo.AssertNumberOfCalls(t, "UpdateMessageStatus", 3)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, parts[2])
+}
+
+func TestSendMessage_MultipartMarksParentSent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	parentID := uint(1)
+	parts := []domain.Message{
+		{ID: 2, ParentID: &parentID, To: "+905551111001", Content: "a (1/2)", Status: domain.StatusPending,
+			PartNumber: 1, PartTotal: 2},
+		{ID: 3, ParentID: &parentID, To: "+905551111001", Content: "b (2/2)", Status: domain.StatusPending,
+			PartNumber: 2, PartTotal: 2},
+	}
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "ab", Status: domain.StatusPending,
+		PartTotal: 2, Parts: parts}
+
+	messageSender.On("Send", anyCtx, parts[0]).Return("msg-id-2", nil).Once()
+	messageSender.On("Send", anyCtx, parts[1]).Return("msg-id-3", nil).Once()
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(3, domain.StatusSent), domain.StatusPending).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == 1 && msg.Status == domain.StatusSent && msg.ProviderMessageID == "msg-id-2"
+	}), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	err := service.SendMessage(ctx, message)
+
+	// Assert
+	assert.NoError(t, err)
+	messageSender.AssertExpectations(t)
+	messageRepo.AssertExpectations(t)
+}
@@ -0,0 +1,221 @@
+package message_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestProcessDeliveryReceipt_Delivered(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{ID: 1, To: "+905551111001", Status: domain.StatusSent, ProviderMessageID: "msg-12345"}
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-12345", Status: domain.StatusDelivered}
+
+	// Set up expectations
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-12345").Return(message, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusDelivered), domain.StatusSent).Return(nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.NoError(t, err)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestProcessDeliveryReceipt_UndeliveredKeepsReason(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender))
+
+	message := &domain.Message{ID: 1, To: "+905551111001", Status: domain.StatusSent, ProviderMessageID: "msg-12345"}
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-12345", Status: domain.StatusUndelivered,
+		ErrorCode: "ABSENT_SUBSCRIBER"}
+
+	// Set up expectations
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-12345").Return(message, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == 1 && msg.Status == domain.StatusUndelivered && msg.FailureReason == "ABSENT_SUBSCRIBER"
+	}), domain.StatusSent).Return(nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.NoError(t, err)
+	messageRepo.AssertExpectations(t)
+}
+
+func TestProcessDeliveryReceipt_Duplicate(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{ID: 1, To: "+905551111001", Status: domain.StatusDelivered, ProviderMessageID: "msg-12345"}
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-12345", Status: domain.StatusDelivered}
+
+	// Set up expectations
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-12345").Return(message, nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.NoError(t, err)
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessDeliveryReceipt_Conflict(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	message := &domain.Message{ID: 1, To: "+905551111001", Status: domain.StatusDelivered, ProviderMessageID: "msg-12345"}
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-12345", Status: domain.StatusUndelivered}
+
+	// Set up expectations
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-12345").Return(message, nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestProcessDeliveryReceipt_UnknownMessage(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-unknown", Status: domain.StatusDelivered}
+
+	// Set up expectations
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-unknown").Return(nil, domain.ErrMessageNotFound)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrMessageNotFound)
+	messageRepo.AssertExpectations(t)
+}
+
+// sentMultipart is a two-part message whose parts are out, the parent sharing part 1's provider ID
+func sentMultipart(part1, part2 domain.Status) *domain.Message {
+	parentID := uint(1)
+	return &domain.Message{ID: 1, To: "+905551111001", Status: domain.StatusSent, ProviderMessageID: "msg-id-2",
+		PartTotal: 2, Parts: []domain.Message{
+			{ID: 2, ParentID: &parentID, To: "+905551111001", Status: part1, ProviderMessageID: "msg-id-2",
+				PartNumber: 1, PartTotal: 2},
+			{ID: 3, ParentID: &parentID, To: "+905551111001", Status: part2, ProviderMessageID: "msg-id-3",
+				PartNumber: 2, PartTotal: 2},
+		}}
+}
+
+func TestProcessDeliveryReceipt_MultipartFirstPart(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	statusNotifier := new(mockedStatusNotifier)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+		services.WithStatusNotifier(statusNotifier))
+
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-id-2", Status: domain.StatusDelivered}
+
+	// Set up expectations - part 1's ID finds the parent, only the part is delivered so far
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-id-2").
+		Return(sentMultipart(domain.StatusSent, domain.StatusSent), nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusDelivered), domain.StatusSent).Return(nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.NoError(t, err)
+	messageRepo.AssertExpectations(t)
+	messageRepo.AssertNumberOfCalls(t, "UpdateMessageStatus", 1)
+	statusNotifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+func TestProcessDeliveryReceipt_MultipartLastPartDeliversParent(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	statusNotifier := new(mockedStatusNotifier)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+		services.WithStatusNotifier(statusNotifier))
+
+	parent := sentMultipart(domain.StatusDelivered, domain.StatusSent)
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-id-3", Status: domain.StatusDelivered}
+
+	// Set up expectations - part 2's ID finds the part, which leads to the parent
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-id-3").Return(&parent.Parts[1], nil)
+	messageRepo.On("GetMessage", anyCtx, uint(1)).Return(parent, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(3, domain.StatusDelivered), domain.StatusSent).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusDelivered), domain.StatusSent).Return(nil)
+	statusNotifier.On("Notify", anyCtx, withStatus(1, domain.StatusDelivered)).Return(nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.NoError(t, err)
+	messageRepo.AssertExpectations(t)
+	statusNotifier.AssertExpectations(t)
+}
+
+func TestProcessDeliveryReceipt_MultipartUndeliveredPart(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender))
+
+	receipt := domain.DeliveryReceipt{ProviderMessageID: "msg-id-2", Status: domain.StatusUndelivered,
+		ErrorCode: "ABSENT_SUBSCRIBER"}
+
+	// Set up expectations - one part failing is enough to fail the message
+	messageRepo.On("GetMessageByProviderID", anyCtx, "msg-id-2").
+		Return(sentMultipart(domain.StatusSent, domain.StatusSent), nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == 2 && msg.Status == domain.StatusUndelivered && msg.FailureReason == "ABSENT_SUBSCRIBER"
+	}), domain.StatusSent).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == 1 && msg.Status == domain.StatusUndelivered && msg.FailureReason == "part 1/2: ABSENT_SUBSCRIBER"
+	}), domain.StatusSent).Return(nil)
+
+	// Act
+	err := service.ProcessDeliveryReceipt(ctx, receipt)
+
+	// Assert
+	assert.NoError(t, err)
+	messageRepo.AssertExpectations(t)
+}
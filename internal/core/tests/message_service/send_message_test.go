@@ -3,6 +3,7 @@ package message_service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/hasElvin/messenger-svc/internal/core/services"
 	"github.com/stretchr/testify/assert"
@@ -21,13 +22,13 @@ func TestSendMessage_Success(t *testing.T) {
 	service := services.NewMessageService(messageRepo, cacheService, messageSender)
 
 	// Mock data
-	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending}
 	expectedMessageID := "msg-12345"
 
 	// Set up expectations
-	messageSender.On("Send", ctx, message).Return(expectedMessageID, nil)
-	messageRepo.On("UpdateMessageStatus", ctx, uint(1), domain.StatusSent).Return(nil)
-	cacheService.On("Set", ctx, "msg:1", mock.MatchedBy(func(value string) bool {
+	messageSender.On("Send", anyCtx, message).Return(expectedMessageID, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.MatchedBy(func(value string) bool {
 		// Verify cache value contains messageId and sentAt
 		return assert.Contains(t, value, "messageId="+expectedMessageID) &&
 			assert.Contains(t, value, "sentAt=")
@@ -54,11 +55,11 @@ func TestSendMessage_SendError(t *testing.T) {
 	service := services.NewMessageService(messageRepo, cacheService, messageSender)
 
 	// Mock data
-	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending}
 	expectedError := errors.New("send failed")
 
 	// Set up expectations - Send will fail
-	messageSender.On("Send", ctx, message).Return("", expectedError)
+	messageSender.On("Send", anyCtx, message).Return("", expectedError)
 
 	// Act
 	err := service.SendMessage(ctx, message)
@@ -84,13 +85,13 @@ func TestSendMessage_UpdateStatusError(t *testing.T) {
 	service := services.NewMessageService(messageRepo, cacheService, messageSender)
 
 	// Mock data
-	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending}
 	expectedMessageID := "msg-12345"
 	updateError := errors.New("update failed")
 
 	// Set up expectations - UpdateMessageStatus will fail
-	messageSender.On("Send", ctx, message).Return(expectedMessageID, nil)
-	messageRepo.On("UpdateMessageStatus", ctx, uint(1), domain.StatusSent).Return(updateError)
+	messageSender.On("Send", anyCtx, message).Return(expectedMessageID, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(updateError)
 
 	// Act
 	err := service.SendMessage(ctx, message)
@@ -116,14 +117,14 @@ func TestSendMessage_CacheError(t *testing.T) {
 	service := services.NewMessageService(messageRepo, cacheService, messageSender)
 
 	// Mock data
-	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1"}
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending}
 	expectedMessageID := "msg-12345"
 	cacheError := errors.New("cache failed")
 
 	// Set up expectations - Caching will fail
-	messageSender.On("Send", ctx, message).Return(expectedMessageID, nil)
-	messageRepo.On("UpdateMessageStatus", ctx, uint(1), domain.StatusSent).Return(nil)
-	cacheService.On("Set", ctx, "msg:1", mock.AnythingOfType("string")).Return(cacheError)
+	messageSender.On("Send", anyCtx, message).Return(expectedMessageID, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(cacheError)
 
 	// Act
 	err := service.SendMessage(ctx, message)
@@ -135,3 +136,31 @@ func TestSendMessage_CacheError(t *testing.T) {
 	messageRepo.AssertExpectations(t)
 	cacheService.AssertExpectations(t)
 }
+
+func TestSendMessage_StatusConflict(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Mock data
+	message := domain.Message{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending}
+	expectedMessageID := "msg-12345"
+	conflictError := fmt.Errorf("%w: message 1 is no longer pending", domain.ErrStatusConflict)
+
+	// Set up expectations - another writer changed the status first
+	messageSender.On("Send", anyCtx, message).Return(expectedMessageID, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).
+		Return(conflictError)
+
+	// Act
+	err := service.SendMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrStatusConflict)
+	cacheService.AssertNotCalled(t, "Set")
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/hasElvin/messenger-svc/config"
 	"github.com/hasElvin/messenger-svc/internal/core/domain"
 	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestSendPendingMessages_Success(t *testing.T) {
@@ -26,22 +28,22 @@ func TestSendPendingMessages_Success(t *testing.T) {
 
 	// Mock data
 	messages := []domain.Message{
-		{ID: 1, To: "+905551111001", Content: "Test message 1"},
-		{ID: 2, To: "+905551111001", Content: "Test message 2"},
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+		{ID: 2, To: "+905551111001", Content: "Test message 2", Status: domain.StatusPending},
 	}
 
 	// Set up expectations
-	messageRepo.On("GetPendingMessages", ctx, 2, mock.Anything, mock.Anything).Return(messages, nil)
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
 
 	// Mock sendMessage calls (these will be called for each message)
-	messageSender.On("Send", ctx, messages[0]).Return("msg-id-1", nil)
-	messageSender.On("Send", ctx, messages[1]).Return("msg-id-2", nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("msg-id-1", nil)
+	messageSender.On("Send", anyCtx, messages[1]).Return("msg-id-2", nil)
 
-	messageRepo.On("UpdateMessageStatus", ctx, uint(1), domain.StatusSent).Return(nil)
-	messageRepo.On("UpdateMessageStatus", ctx, uint(2), domain.StatusSent).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
 
-	cacheService.On("Set", ctx, "msg:1", mock.AnythingOfType("string")).Return(nil)
-	cacheService.On("Set", ctx, "msg:2", mock.AnythingOfType("string")).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:2", mock.AnythingOfType("string")).Return(nil)
 
 	// Act
 	service.SendPendingMessages(ctx, cfg)
@@ -67,7 +69,7 @@ func TestSendPendingMessages_GetPendingMessagesError(t *testing.T) {
 	service := services.NewMessageService(messageRepo, cacheService, messageSender)
 
 	// Set up expectations - repo returns error
-	messageRepo.On("GetPendingMessages", ctx, 2, mock.Anything, mock.Anything).
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).
 		Return([]domain.Message{}, errors.New("database error"))
 
 	// Act
@@ -97,14 +99,14 @@ func TestSendPendingMessages_SendMessageError_SingleMessage(t *testing.T) {
 
 	// Mock data - message with RetryCount = 0 (first attempt)
 	messages := []domain.Message{
-		{ID: 1, To: "+905551111001", Content: "Test message 1", RetryCount: 0},
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, RetryCount: 0},
 	}
 
 	// Set up expectations - sendMessage will fail
-	messageRepo.On("GetPendingMessages", ctx, 2, mock.Anything, mock.Anything).Return(messages, nil)
-	messageSender.On("Send", ctx, messages[0]).Return("", errors.New("send error"))
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("", errors.New("send error"))
 
-	messageRepo.On("IncrementRetryCount", ctx, uint(1)).Return(nil)
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(1)).Return(nil)
 
 	// Act
 	service.SendPendingMessages(ctx, cfg)
@@ -137,21 +139,21 @@ func TestSendPendingMessages_SendMessageError_MultipleMessages(t *testing.T) {
 
 	// Mock data
 	messages := []domain.Message{
-		{ID: 1, To: "+905551111001", Content: "Test message 1", RetryCount: 0}, // This will fail
-		{ID: 2, To: "+905551111002", Content: "Test message 2", RetryCount: 0}, // This will succeed
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, RetryCount: 0}, // This will fail
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending, RetryCount: 0}, // This will succeed
 	}
 
 	// Set up expectations - sendMessage will fail for first message
-	messageRepo.On("GetPendingMessages", ctx, 2, mock.Anything, mock.Anything).Return(messages, nil)
-	messageSender.On("Send", ctx, messages[0]).Return("", errors.New("send error"))
-	messageSender.On("Send", ctx, messages[1]).Return("msg-id-2", nil)
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("", errors.New("send error"))
+	messageSender.On("Send", anyCtx, messages[1]).Return("msg-id-2", nil)
 
 	// First message fails - only IncrementRetryCount should be called (not at max retries)
-	messageRepo.On("IncrementRetryCount", ctx, uint(1)).Return(nil)
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(1)).Return(nil)
 
 	// The second message should still update status and cache
-	messageRepo.On("UpdateMessageStatus", ctx, uint(2), domain.StatusSent).Return(nil)
-	cacheService.On("Set", ctx, "msg:2", mock.AnythingOfType("string")).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:2", mock.AnythingOfType("string")).Return(nil)
 
 	// Act
 	service.SendPendingMessages(ctx, cfg)
@@ -162,8 +164,8 @@ func TestSendPendingMessages_SendMessageError_MultipleMessages(t *testing.T) {
 	cacheService.AssertExpectations(t)
 
 	// UpdateMessageStatus and cache Set should not be called for the first message
-	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", ctx, uint(1), domain.StatusSent)
-	cacheService.AssertNotCalled(t, "Set", ctx, "msg:1", mock.AnythingOfType("string"))
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), mock.Anything)
+	cacheService.AssertNotCalled(t, "Set", anyCtx, "msg:1", mock.AnythingOfType("string"))
 }
 
 func TestSendPendingMessages_SendMessageError_MaxRetriesReached(t *testing.T) {
@@ -183,15 +185,15 @@ func TestSendPendingMessages_SendMessageError_MaxRetriesReached(t *testing.T) {
 
 	// Mock data - message with RetryCount = 2 (will reach max retries after increment)
 	messages := []domain.Message{
-		{ID: 1, To: "+905551111001", Content: "Test message 1", RetryCount: 2},
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, RetryCount: 2},
 	}
 
 	// Set up expectations - sendMessage will fail
-	messageRepo.On("GetPendingMessages", ctx, 2, mock.Anything, mock.Anything).Return(messages, nil)
-	messageSender.On("Send", ctx, messages[0]).Return("", errors.New("send error"))
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("", errors.New("send error"))
 
-	messageRepo.On("IncrementRetryCount", ctx, uint(1)).Return(nil)
-	messageRepo.On("UpdateMessageStatus", ctx, uint(1), domain.StatusFailed).Return(nil)
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(1)).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusFailed), domain.StatusPending).Return(nil)
 
 	// Act
 	service.SendPendingMessages(ctx, cfg)
@@ -203,3 +205,374 @@ func TestSendPendingMessages_SendMessageError_MaxRetriesReached(t *testing.T) {
 	// Cache Set should not be called when Send fails
 	cacheService.AssertNotCalled(t, "Set")
 }
+
+func TestSendPendingMessages_CircuitOpen(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Mock data - acme's webhook is down, globex's is fine
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, TenantID: "acme"},
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending, TenantID: "acme"},
+		{ID: 3, To: "+905551111003", Content: "Test message 3", Status: domain.StatusPending, TenantID: "globex"},
+	}
+	cfg.App.BatchSize = 3
+
+	// Set up expectations - the breaker rejects acme's first send
+	messageRepo.On("GetPendingMessages", anyCtx, 3, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("", domain.ErrCircuitOpen)
+	messageSender.On("Send", anyCtx, messages[2]).Return("msg-id-3", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(3, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:3", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+
+	// Rejected sends are not retries and the tenant's other messages are skipped
+	messageRepo.AssertNotCalled(t, "IncrementRetryCount", mock.Anything, mock.Anything)
+	messageRepo.AssertNumberOfCalls(t, "UpdateMessageStatus", 1)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[1])
+}
+
+func TestSendPendingMessages_ShutdownFinishesInFlightSend(t *testing.T) {
+	// Arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Mock data
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending},
+	}
+	live := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Err() == nil })
+
+	// Set up expectations - shutdown starts while the first message is with the provider
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Run(func(mock.Arguments) { cancel() }).Return("msg-id-1", nil)
+	messageRepo.On("UpdateMessageStatus", live, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", live, "msg:1", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	cacheService.AssertExpectations(t)
+
+	// The started send is recorded, the next one is left pending
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[1])
+}
+
+func TestSendPendingMessages_RateLimitedDeferred(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Mock data
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, RetryCount: 0}, // Over the limit
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending, RetryCount: 0}, // Within the limit
+	}
+	deferred := &domain.DeferredError{Reason: domain.ErrRateLimited, RetryAfter: 30 * time.Second}
+
+	// Set up expectations - the first message is held back by the rate limiter
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("", deferred)
+	messageSender.On("Send", anyCtx, messages[1]).Return("msg-id-2", nil)
+
+	messageRepo.On("DeferMessage", anyCtx, uint(1), mock.MatchedBy(func(until time.Time) bool {
+		return until.After(time.Now().Add(20 * time.Second))
+	})).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:2", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertExpectations(t)
+
+	// Deferred messages are neither retried nor failed
+	messageRepo.AssertNotCalled(t, "IncrementRetryCount", anyCtx, uint(1))
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusFailed), mock.Anything)
+}
+
+func TestSendPendingMessages_OverLimitFlaggedAsFailed(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 10
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	// Mock data - inserted directly into the database, bypassing enqueue validation
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+	}
+
+	// Set up expectations
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == 1 && msg.Status == domain.StatusFailed && msg.FailureReason != ""
+	}), domain.StatusPending).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestSendPendingMessages_SuppressedRecipient(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	suppressions := new(mockedSuppressionChecker)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithSuppressions(suppressions))
+
+	// Mock data
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending},
+	}
+
+	// Set up expectations
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	suppressions.On("IsSuppressed", anyCtx, "+905551111001").Return(true, nil)
+	suppressions.On("IsSuppressed", anyCtx, "+905551111002").Return(false, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSuppressed), domain.StatusPending).Return(nil)
+	messageSender.On("Send", anyCtx, messages[1]).Return("msg-id-2", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:2", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[0])
+}
+
+func TestSendPendingMessages_SuppressionCheckFails(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	suppressions := new(mockedSuppressionChecker)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithSuppressions(suppressions))
+
+	// Mock data
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+	}
+
+	// Set up expectations
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	suppressions.On("IsSuppressed", anyCtx, "+905551111001").Return(false, errors.New("connection refused"))
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+	messageRepo.AssertNotCalled(t, "IncrementRetryCount", mock.Anything, mock.Anything)
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendPendingMessages_QuietHoursDeferred(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// A window from an hour ago to an hour from now in the recipient's zone
+	baku, _ := time.LoadLocation("Asia/Baku")
+	local := time.Now().In(baku)
+	window, err := domain.ParseQuietWindow(local.Add(-time.Hour).Format("15:04"), local.Add(time.Hour).Format("15:04"))
+	assert.NoError(t, err)
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithQuietHours(map[string]domain.QuietWindow{"marketing": window}))
+
+	// Mock data
+	messages := []domain.Message{
+		{ID: 1, To: "+994501234567", Content: "Sale!", Status: domain.StatusPending,
+			Category: "marketing", Timezone: "Asia/Baku"},
+		{ID: 2, To: "+994501234567", Content: "Your code is 1234", Status: domain.StatusPending,
+			Category: "otp", Timezone: "Asia/Baku"},
+	}
+
+	// Set up expectations
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageRepo.On("DeferMessage", anyCtx, uint(1), mock.MatchedBy(func(until time.Time) bool {
+		return until.After(time.Now().Add(50*time.Minute)) && until.Before(time.Now().Add(61*time.Minute))
+	})).Return(nil)
+	messageSender.On("Send", anyCtx, messages[1]).Return("msg-id-2", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:2", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[0])
+	messageRepo.AssertNotCalled(t, "IncrementRetryCount", mock.Anything, mock.Anything)
+}
+
+func TestSendPendingMessages_DuplicateCollapsed(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithDedupe(services.DedupeCollapse, time.Minute))
+
+	// Mock data, the second message was already claimed by message 1 and the first is a
+	// retry that finds its own claim
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, DedupeHash: "abc"},
+		{ID: 2, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending, DedupeHash: "abc"},
+	}
+
+	// Set up expectations
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	cacheService.On("SetIfAbsent", anyCtx, "dedupe:abc", mock.AnythingOfType("string"), time.Minute).Return(false, nil)
+	cacheService.On("Get", anyCtx, "dedupe:abc").Return("1", nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("msg-id-1", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, mock.MatchedBy(func(msg domain.Message) bool {
+		return msg.ID == 2 && msg.Status == domain.StatusDuplicate && msg.DuplicateOf != nil && *msg.DuplicateOf == 1
+	}), domain.StatusPending).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[1])
+}
+
+func TestSendPendingMessages_RecordsMetrics(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	metrics := new(mockedDispatchMetrics)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+
+	// Create service instance
+	service := services.NewMessageService(messageRepo, cacheService, messageSender,
+		services.WithMetrics(metrics))
+
+	// Mock data
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending},
+	}
+	sendErr := &domain.ProviderError{StatusCode: 503}
+
+	// Set up expectations
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("msg-id-1", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(nil)
+	messageSender.On("Send", anyCtx, messages[1]).Return("", sendErr)
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(2)).Return(nil)
+	metrics.On("MessageSent").Return()
+	metrics.On("MessageRetried", sendErr).Return()
+	metrics.On("ObserveTick", mock.AnythingOfType("time.Duration")).Return()
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+
+	// Assert
+	metrics.AssertExpectations(t)
+	metrics.AssertNumberOfCalls(t, "MessageSent", 1)
+	metrics.AssertNotCalled(t, "MessageFailed", mock.Anything)
+}
@@ -0,0 +1,145 @@
+package message_service
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestSenderStatus_CountsOutcomesAndBatch(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	cfg := &config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+	cfg.App.BatchSize = 5
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending},
+	}
+
+	messageRepo.On("GetPendingMessages", anyCtx, 5, 3).Return(messages, nil)
+	messageSender.On("Send", anyCtx, messages[0]).Return("", errors.New("send error"))
+	messageSender.On("Send", anyCtx, messages[1]).Return("msg-id-2", nil)
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(1)).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:2", mock.AnythingOfType("string")).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, cfg)
+	status := service.SenderStatus()
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	assert.False(t, status.Running)
+	assert.Nil(t, status.NextTickAt)
+	assert.Equal(t, 2, status.LastBatchSize)
+	assert.Equal(t, domain.SenderCounters{Sent: 1, Retried: 1}, status.Counters)
+}
+
+func TestStartAutoSender_RejectsInvalidOptions(t *testing.T) {
+	// Arrange
+	service := services.NewMessageService(new(mockedMessageRepo), new(mockedCacheService), new(mockedMessageSender))
+
+	// Act - without a configured interval there is nothing to fall back on
+	noInterval := service.StartAutoSender(context.Background(), 0, 10)
+	negativeBatch := service.StartAutoSender(context.Background(), 5, -1)
+
+	// Assert
+	assert.Error(t, noInterval)
+	assert.Error(t, negativeBatch)
+	assert.False(t, service.SenderStatus().Running)
+}
+
+func TestStartAutoSender_FollowsConfigUnlessOverridden(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.App.SendIntervalSecs = 60
+	cfg.App.BatchSize = 10
+	provider := new(mockedConfigProvider)
+	provider.On("Current").Return(cfg)
+
+	service := services.NewMessageService(new(mockedMessageRepo), new(mockedCacheService), new(mockedMessageSender),
+		services.WithConfig(provider))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Act
+	err := service.StartAutoSender(ctx, 30, 0)
+	running := service.SenderStatus()
+	cancel()
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), time.Second)
+	defer drainCancel()
+	drainErr := service.Drain(drainCtx)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, running.Running)
+	assert.Equal(t, 30, running.IntervalSeconds)
+	assert.Equal(t, 10, running.BatchSize)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), *running.NextTickAt, time.Second)
+	assert.Nil(t, running.LastTickAt)
+
+	assert.NoError(t, drainErr)
+	assert.False(t, service.SenderStatus().Running)
+}
+
+func TestDrain_CancelsBatchOfRestartedSender(t *testing.T) {
+	// Arrange
+	cfg := config.Config{}
+	cfg.App.SendIntervalSecs = 1
+	cfg.App.BatchSize = 2
+	provider := new(mockedConfigProvider)
+	provider.On("Current").Return(cfg)
+
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+	service := services.NewMessageService(messageRepo, cacheService, messageSender, services.WithConfig(provider))
+
+	messages := []domain.Message{
+		{ID: 1, To: "+905551111001", Content: "Test message 1", Status: domain.StatusPending},
+		{ID: 2, To: "+905551111002", Content: "Test message 2", Status: domain.StatusPending},
+	}
+	sending, release := make(chan struct{}), make(chan struct{})
+	messageRepo.On("GetPendingMessages", anyCtx, 2, mock.Anything).Return(messages, nil).Once()
+	messageSender.On("Send", anyCtx, messages[0]).Run(func(mock.Arguments) {
+		close(sending)
+		<-release
+	}).Return("msg-id-1", nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusSent), domain.StatusPending).Return(nil)
+	cacheService.On("Set", anyCtx, "msg:1", mock.AnythingOfType("string")).Return(nil)
+
+	// Restarted from a request, with a context nothing else cancels
+	assert.NoError(t, service.StartAutoSender(context.Background(), 0, 0))
+	assert.NoError(t, service.StopAutoSender(context.Background()))
+	assert.NoError(t, service.StartAutoSender(context.Background(), 0, 0))
+	<-sending
+
+	// Act
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer drainCancel()
+	drained := make(chan error)
+	go func() { drained <- service.Drain(drainCtx) }()
+	assert.Eventually(t, func() bool { return !service.SenderStatus().Running }, time.Second, 10*time.Millisecond)
+	close(release)
+
+	// Assert
+	assert.NoError(t, <-drained)
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[1])
+}
@@ -0,0 +1,196 @@
+package message_service
+
+import (
+	"context"
+	"errors"
+	"github.com/hasElvin/messenger-svc/config"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"strings"
+	"testing"
+)
+
+func tenantConfig() config.Config {
+	cfg := config.Config{}
+	cfg.App.MessageCharLimit = 1000
+	cfg.App.MaxRetries = 3
+	cfg.Tenants = map[string]config.Tenant{
+		"marketing": {MessageCharLimit: 10, MaxRetries: 1},
+		"payments":  {MaxRetries: 5},
+	}
+	return cfg
+}
+
+func TestEnqueueMessage_TenantCharLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	configProvider := new(mockedConfigProvider)
+	configProvider.On("Current").Return(tenantConfig())
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+		services.WithConfig(configProvider), services.WithContentLimits(1000, 0))
+
+	message := &domain.Message{TenantID: "marketing", To: "+905551111001", Content: strings.Repeat("a", 11)}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	assert.Contains(t, err.Error(), "limit is 10")
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestEnqueueMessage_UnknownTenant(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	configProvider := new(mockedConfigProvider)
+	configProvider.On("Current").Return(tenantConfig())
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+		services.WithConfig(configProvider))
+
+	message := &domain.Message{TenantID: "support", To: "+905551111001", Content: "Hello"}
+
+	// Act
+	err := service.EnqueueMessage(ctx, message)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	messageRepo.AssertNotCalled(t, "CreateMessage", mock.Anything, mock.Anything)
+}
+
+func TestSendPendingMessages_TenantRetryLimits(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	cacheService := new(mockedCacheService)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, cacheService, messageSender)
+
+	cfg := tenantConfig()
+	messages := []domain.Message{
+		// Already used up the single attempt marketing allows
+		{ID: 1, TenantID: "marketing", To: "+905551111001", Content: "Sale", Status: domain.StatusPending, RetryCount: 1},
+		// Past the app-wide limit but payments allows more
+		{ID: 2, TenantID: "payments", To: "+905551111002", Content: "Receipt", Status: domain.StatusPending, RetryCount: 3},
+	}
+
+	// The query has to let through the retries of the most lenient tenant
+	messageRepo.On("GetPendingMessages", anyCtx, 2, 5).Return(messages, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusFailed), domain.StatusPending).Return(nil)
+	messageSender.On("Send", anyCtx, messages[1]).Return("", errors.New("provider down"))
+	messageRepo.On("IncrementRetryCount", anyCtx, uint(2)).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, &cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", anyCtx, messages[0])
+	messageRepo.AssertNotCalled(t, "UpdateMessageStatus", anyCtx, withStatus(2, domain.StatusFailed), domain.StatusPending)
+}
+
+func TestSendPendingMessages_TenantCharLimit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+	messageSender := new(mockedMessageSender)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), messageSender)
+
+	cfg := tenantConfig()
+	messages := []domain.Message{
+		{ID: 1, TenantID: "marketing", To: "+905551111001", Content: "Written straight to the table", Status: domain.StatusPending},
+	}
+
+	messageRepo.On("GetPendingMessages", anyCtx, 2, 5).Return(messages, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(1, domain.StatusFailed), domain.StatusPending).Return(nil)
+
+	// Act
+	service.SendPendingMessages(ctx, &cfg)
+
+	// Assert
+	messageRepo.AssertExpectations(t)
+	messageSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestCancelMessage(t *testing.T) {
+	cases := map[string]struct {
+		tenantID string
+		stored   domain.Message
+		err      error
+	}{
+		"own tenant":              {tenantID: "marketing", stored: domain.Message{ID: 7, TenantID: "marketing", Status: domain.StatusPending}},
+		"deployment-wide":         {stored: domain.Message{ID: 7, TenantID: "marketing", Status: domain.StatusPending}},
+		"other tenant":            {tenantID: "payments", stored: domain.Message{ID: 7, TenantID: "marketing", Status: domain.StatusPending}, err: domain.ErrMessageNotFound},
+		"already sent":            {tenantID: "marketing", stored: domain.Message{ID: 7, TenantID: "marketing", Status: domain.StatusSent}, err: domain.ErrInvalidTransition},
+		"picked up by the sender": {tenantID: "marketing", stored: domain.Message{ID: 7, TenantID: "marketing", Status: domain.StatusPending}, err: domain.ErrStatusConflict},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			messageRepo := new(mockedMessageRepo)
+			notifier := new(mockedStatusNotifier)
+
+			service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender),
+				services.WithStatusNotifier(notifier))
+
+			stored := tc.stored
+			messageRepo.On("GetMessage", anyCtx, uint(7)).Return(&stored, nil)
+			var updateErr error
+			if errors.Is(tc.err, domain.ErrStatusConflict) {
+				updateErr = domain.ErrStatusConflict
+			}
+			messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(7, domain.StatusCancelled), domain.StatusPending).Return(updateErr)
+			notifier.On("Notify", anyCtx, withStatus(7, domain.StatusCancelled)).Return(nil)
+
+			// Act
+			msg, err := service.CancelMessage(ctx, 7, tc.tenantID)
+
+			// Assert
+			assert.ErrorIs(t, err, tc.err)
+			if tc.err == nil {
+				assert.Equal(t, domain.StatusCancelled, msg.Status)
+				notifier.AssertExpectations(t)
+			} else {
+				notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+			}
+			if errors.Is(tc.err, domain.ErrMessageNotFound) || errors.Is(tc.err, domain.ErrInvalidTransition) {
+				messageRepo.AssertNotCalled(t, "UpdateMessageStatus", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestCancelMessage_CancelsUnsentParts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	messageRepo := new(mockedMessageRepo)
+
+	service := services.NewMessageService(messageRepo, new(mockedCacheService), new(mockedMessageSender))
+
+	stored := &domain.Message{ID: 7, Status: domain.StatusPending, PartTotal: 2, Parts: []domain.Message{
+		{ID: 8, Status: domain.StatusSent, PartNumber: 1, PartTotal: 2},
+		{ID: 9, Status: domain.StatusPending, PartNumber: 2, PartTotal: 2},
+	}}
+	messageRepo.On("GetMessage", anyCtx, uint(7)).Return(stored, nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(7, domain.StatusCancelled), domain.StatusPending).Return(nil)
+	messageRepo.On("UpdateMessageStatus", anyCtx, withStatus(9, domain.StatusCancelled), domain.StatusPending).Return(nil)
+
+	// Act
+	msg, err := service.CancelMessage(ctx, 7, "")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusSent, msg.Parts[0].Status)
+	assert.Equal(t, domain.StatusCancelled, msg.Parts[1].Status)
+	messageRepo.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package message_status
+
+import (
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestStatus_AllowedTransitions(t *testing.T) {
+	assert.True(t, domain.StatusPending.CanTransitionTo(domain.StatusSent))
+	assert.True(t, domain.StatusPending.CanTransitionTo(domain.StatusFailed))
+	assert.True(t, domain.StatusSent.CanTransitionTo(domain.StatusDelivered))
+	assert.True(t, domain.StatusSent.CanTransitionTo(domain.StatusUndelivered))
+}
+
+func TestStatus_RejectedTransitions(t *testing.T) {
+	// A late retry must not flip a sent message back to failed
+	assert.False(t, domain.StatusSent.CanTransitionTo(domain.StatusFailed))
+	assert.False(t, domain.StatusSent.CanTransitionTo(domain.StatusPending))
+	assert.False(t, domain.StatusFailed.CanTransitionTo(domain.StatusSent))
+	assert.False(t, domain.StatusDelivered.CanTransitionTo(domain.StatusUndelivered))
+	assert.False(t, domain.StatusPending.CanTransitionTo(domain.StatusDelivered))
+
+	assert.True(t, domain.StatusDelivered.IsTerminal())
+	assert.False(t, domain.StatusPending.IsTerminal())
+}
+
+func TestMessage_MarkSent(t *testing.T) {
+	// Arrange
+	message := domain.Message{ID: 1, Status: domain.StatusPending}
+	sentAt := time.Now()
+
+	// Act
+	err := message.MarkSent("msg-12345", sentAt)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusSent, message.Status)
+	assert.Equal(t, "msg-12345", message.ProviderMessageID)
+	assert.Equal(t, &sentAt, message.SentAt)
+}
+
+func TestMessage_MarkFailedAfterSent(t *testing.T) {
+	// Arrange
+	message := domain.Message{ID: 1, Status: domain.StatusSent}
+
+	// Act
+	err := message.MarkFailed("send error", time.Now())
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	assert.Equal(t, domain.StatusSent, message.Status)
+}
@@ -0,0 +1,57 @@
+package netguard
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/hasElvin/messenger-svc/internal/core/netguard"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.100.100.200", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			// Act
+			public := netguard.PublicAddr(netip.MustParseAddr(tt.addr))
+
+			// Assert
+			assert.Equal(t, tt.public, public)
+		})
+	}
+}
+
+func TestControl_RejectsResolvedInternalAddress(t *testing.T) {
+	// Act
+	err := netguard.Control("tcp4", "10.0.0.5:443", nil)
+
+	// Assert
+	assert.ErrorIs(t, err, netguard.ErrForbiddenAddress)
+	assert.NoError(t, netguard.Control("tcp4", "93.184.216.34:443", nil))
+}
+
+func TestCheckURL(t *testing.T) {
+	// Assert
+	assert.NoError(t, netguard.CheckURL("https://hooks.example.com/sms"))
+	assert.ErrorIs(t, netguard.CheckURL("http://api.localhost/hook"), netguard.ErrForbiddenAddress)
+	assert.ErrorIs(t, netguard.CheckURL("http://[fd00::1]/hook"), netguard.ErrForbiddenAddress)
+	assert.Error(t, netguard.CheckURL("ftp://example.com"))
+}
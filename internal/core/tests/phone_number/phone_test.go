@@ -0,0 +1,81 @@
+package phone_number
+
+import (
+	"github.com/hasElvin/messenger-svc/internal/core/phone"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse_NormalizesTurkishFormats(t *testing.T) {
+	inputs := []string{
+		"+905551234567",
+		"+90 555 123 45 67",
+		"05551234567",
+		"0555 123 45 67",
+		"5551234567",
+		"905551234567",
+		"00905551234567",
+		"+90 (0555) 123-45-67",
+	}
+
+	for _, input := range inputs {
+		number, err := phone.Parse(input, "TR")
+
+		assert.NoError(t, err, input)
+		assert.Equal(t, "+905551234567", number.E164, input)
+		assert.Equal(t, "TR", number.Region, input)
+		assert.Equal(t, "90", number.CallingCode, input)
+		assert.Equal(t, phone.Mobile, number.Type, input)
+	}
+}
+
+func TestParse_InternationalIgnoresDefaultRegion(t *testing.T) {
+	number, err := phone.Parse("+44 7911 123456", "TR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "+447911123456", number.E164)
+	assert.Equal(t, "GB", number.Region)
+}
+
+func TestParse_NorthAmericanExitCode(t *testing.T) {
+	number, err := phone.Parse("011 90 555 123 45 67", "US")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "+905551234567", number.E164)
+
+	number, err = phone.Parse("(212) 555-0100", "US")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "+12125550100", number.E164)
+	assert.Equal(t, phone.Unknown, number.Type)
+}
+
+func TestParse_RejectsWrongLength(t *testing.T) {
+	_, err := phone.Parse("0555123456", "TR")
+
+	assert.ErrorIs(t, err, phone.ErrInvalidNumber)
+}
+
+func TestParse_RejectsFixedLine(t *testing.T) {
+	_, err := phone.Parse("0212 123 45 67", "TR")
+
+	assert.ErrorIs(t, err, phone.ErrNotMobile)
+}
+
+func TestParse_RejectsLetters(t *testing.T) {
+	_, err := phone.Parse("+90 555 CALL ME", "TR")
+
+	assert.ErrorIs(t, err, phone.ErrInvalidNumber)
+}
+
+func TestParse_NationalNumberNeedsDefaultRegion(t *testing.T) {
+	_, err := phone.Parse("05551234567", "")
+
+	assert.ErrorIs(t, err, phone.ErrInvalidNumber)
+}
+
+func TestParse_UnsupportedCountry(t *testing.T) {
+	_, err := phone.Parse("+8613812345678", "TR")
+
+	assert.ErrorIs(t, err, phone.ErrUnsupportedCountry)
+}
@@ -0,0 +1,56 @@
+package quiet_hours
+
+import (
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseQuietWindow_Invalid(t *testing.T) {
+	_, err := domain.ParseQuietWindow("25:00", "09:00")
+	assert.Error(t, err)
+
+	_, err = domain.ParseQuietWindow("09:00", "09:00")
+	assert.Error(t, err)
+}
+
+func TestOpensAt_OvernightWindowEvening(t *testing.T) {
+	istanbul, _ := time.LoadLocation("Europe/Istanbul")
+	window, _ := domain.ParseQuietWindow("21:00", "09:00")
+
+	opens, quiet := window.OpensAt(time.Date(2024, 3, 10, 23, 30, 0, 0, istanbul), istanbul)
+
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2024, 3, 11, 9, 0, 0, 0, istanbul), opens)
+}
+
+func TestOpensAt_OvernightWindowMorning(t *testing.T) {
+	istanbul, _ := time.LoadLocation("Europe/Istanbul")
+	window, _ := domain.ParseQuietWindow("21:00", "09:00")
+
+	opens, quiet := window.OpensAt(time.Date(2024, 3, 10, 3, 0, 0, 0, istanbul), istanbul)
+
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2024, 3, 10, 9, 0, 0, 0, istanbul), opens)
+}
+
+func TestOpensAt_OutsideWindow(t *testing.T) {
+	istanbul, _ := time.LoadLocation("Europe/Istanbul")
+	window, _ := domain.ParseQuietWindow("21:00", "09:00")
+
+	_, quiet := window.OpensAt(time.Date(2024, 3, 10, 9, 0, 0, 0, istanbul), istanbul)
+
+	assert.False(t, quiet)
+}
+
+func TestOpensAt_UsesRecipientZone(t *testing.T) {
+	baku, _ := time.LoadLocation("Asia/Baku")
+	window, _ := domain.ParseQuietWindow("21:00", "09:00")
+
+	// 18:30 UTC is 22:30 in Baku
+	opens, quiet := window.OpensAt(time.Date(2024, 3, 10, 18, 30, 0, 0, time.UTC), baku)
+
+	assert.True(t, quiet)
+	assert.Equal(t, time.Date(2024, 3, 11, 5, 0, 0, 0, time.UTC), opens.UTC())
+}
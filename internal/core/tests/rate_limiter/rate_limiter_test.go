@@ -0,0 +1,110 @@
+package rate_limiter
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hasElvin/messenger-svc/internal/adapters/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// newRedis starts an in-memory Redis whose clock the reserve script reads through TIME
+func newRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return server, client
+}
+
+func TestReserve_GlobalBucketAllowsBurstThenWaits(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	_, client := newRedis(t)
+	limiter := cache.NewRedisRateLimiter(client, 2, 2, 0, 0)
+
+	// Act
+	first, firstErr := limiter.Reserve(ctx, "+905551111001")
+	second, _ := limiter.Reserve(ctx, "+905551111002")
+	third, _ := limiter.Reserve(ctx, "+905551111003")
+
+	// Assert
+	assert.NoError(t, firstErr)
+	assert.Zero(t, first)
+	assert.Zero(t, second)
+	assert.Equal(t, 500*time.Millisecond, third)
+}
+
+func TestReserve_GlobalBucketRefills(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	server, client := newRedis(t)
+	limiter := cache.NewRedisRateLimiter(client, 2, 1, 0, 0)
+
+	// Act
+	first, _ := limiter.Reserve(ctx, "+905551111001")
+	blocked, _ := limiter.Reserve(ctx, "+905551111001")
+	server.SetTime(time.Date(2026, 1, 1, 12, 0, 0, int(500*time.Millisecond), time.UTC))
+	refilled, _ := limiter.Reserve(ctx, "+905551111001")
+
+	// Assert
+	assert.Zero(t, first)
+	assert.Equal(t, 500*time.Millisecond, blocked)
+	assert.Zero(t, refilled)
+}
+
+func TestReserve_RecipientWindow(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	server, client := newRedis(t)
+	limiter := cache.NewRedisRateLimiter(client, 0, 0, 2, time.Minute)
+
+	// Act
+	first, _ := limiter.Reserve(ctx, "+905551111001")
+	second, _ := limiter.Reserve(ctx, "+905551111001")
+	blocked, _ := limiter.Reserve(ctx, "+905551111001")
+	otherRecipient, _ := limiter.Reserve(ctx, "+905551111002")
+	server.FastForward(time.Minute)
+	afterWindow, _ := limiter.Reserve(ctx, "+905551111001")
+
+	// Assert
+	assert.Zero(t, first)
+	assert.Zero(t, second)
+	assert.Equal(t, time.Minute, blocked)
+	assert.Zero(t, otherRecipient)
+	assert.Zero(t, afterWindow)
+}
+
+func TestReserve_BlockedRecipientKeepsGlobalToken(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	_, client := newRedis(t)
+	limiter := cache.NewRedisRateLimiter(client, 1, 2, 1, time.Minute)
+
+	// Act
+	first, _ := limiter.Reserve(ctx, "+905551111001")
+	blocked, _ := limiter.Reserve(ctx, "+905551111001")
+	otherRecipient, _ := limiter.Reserve(ctx, "+905551111002")
+
+	// Assert
+	assert.Zero(t, first)
+	assert.Positive(t, blocked)
+	// The rejected reservation didn't take the second token of the burst
+	assert.Zero(t, otherRecipient)
+}
+
+func TestReserve_RedisUnavailable(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	server, client := newRedis(t)
+	limiter := cache.NewRedisRateLimiter(client, 1, 1, 1, time.Minute)
+	server.Close()
+
+	// Act
+	_, err := limiter.Reserve(ctx, "+905551111001")
+
+	// Assert
+	assert.Error(t, err)
+}
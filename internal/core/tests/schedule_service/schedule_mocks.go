@@ -0,0 +1,59 @@
+package schedule_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type mockedScheduleRepo struct {
+	mock.Mock
+}
+
+func (r *mockedScheduleRepo) CreateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	args := r.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (r *mockedScheduleRepo) GetSchedule(ctx context.Context, id uint) (*domain.Schedule, error) {
+	args := r.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Schedule), args.Error(1)
+}
+
+func (r *mockedScheduleRepo) ListSchedules(ctx context.Context) ([]domain.Schedule, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (r *mockedScheduleRepo) UpdateSchedule(ctx context.Context, schedule *domain.Schedule) error {
+	args := r.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (r *mockedScheduleRepo) DeleteSchedule(ctx context.Context, id uint) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (r *mockedScheduleRepo) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]domain.Schedule, error) {
+	args := r.Called(ctx, now, limit)
+	return args.Get(0).([]domain.Schedule), args.Error(1)
+}
+
+func (r *mockedScheduleRepo) AdvanceSchedule(ctx context.Context, id uint, due time.Time, next *time.Time) (bool, error) {
+	args := r.Called(ctx, id, due, next)
+	return args.Bool(0), args.Error(1)
+}
+
+type mockedMessageEnqueuer struct {
+	mock.Mock
+}
+
+func (m *mockedMessageEnqueuer) EnqueueMessage(ctx context.Context, msg *domain.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
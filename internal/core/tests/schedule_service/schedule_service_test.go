@@ -0,0 +1,145 @@
+package schedule_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestCreateSchedule_NormalizesAndComputesNextRun(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := new(mockedScheduleRepo)
+	service := services.NewScheduleService(repo, new(mockedMessageEnqueuer), "TR")
+
+	schedule := &domain.Schedule{Name: " Weekly balance ", Cron: "0 9 * * MON", Timezone: "Europe/Istanbul",
+		TemplateID: 3, Recipients: []string{"0555 111 10 01", "+905551111001", "+905551111002"}, Category: "Reminders"}
+
+	repo.On("CreateSchedule", ctx, schedule).Return(nil)
+
+	// Act
+	err := service.CreateSchedule(ctx, schedule)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Weekly balance", schedule.Name)
+	assert.Equal(t, []string{"+905551111001", "+905551111002"}, schedule.Recipients)
+	assert.Equal(t, "reminders", schedule.Category)
+	if assert.NotNil(t, schedule.NextRunAt) {
+		local := schedule.NextRunAt.In(time.FixedZone("TRT", 3*60*60))
+		assert.Equal(t, time.Monday, local.Weekday())
+		assert.Equal(t, 9, local.Hour())
+		assert.True(t, schedule.NextRunAt.After(time.Now()))
+	}
+}
+
+func TestCreateSchedule_Invalid(t *testing.T) {
+	cases := map[string]domain.Schedule{
+		"bad cron":      {Name: "x", Cron: "every monday", TemplateID: 1, Recipients: []string{"+905551111001"}},
+		"tz prefix":     {Name: "x", Cron: "CRON_TZ=Asia/Baku 0 9 * * *", TemplateID: 1, Recipients: []string{"+905551111001"}},
+		"bad timezone":  {Name: "x", Cron: "0 9 * * *", Timezone: "Mars/Base", TemplateID: 1, Recipients: []string{"+905551111001"}},
+		"no template":   {Name: "x", Cron: "0 9 * * *", Recipients: []string{"+905551111001"}},
+		"no recipients": {Name: "x", Cron: "0 9 * * *", TemplateID: 1},
+		"bad recipient": {Name: "x", Cron: "0 9 * * *", TemplateID: 1, Recipients: []string{"12"}},
+		"end before start": {Name: "x", Cron: "0 9 * * *", TemplateID: 1, Recipients: []string{"+905551111001"},
+			StartAt: timePtr(time.Now().Add(48 * time.Hour)), EndAt: timePtr(time.Now())},
+	}
+
+	for name, schedule := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo := new(mockedScheduleRepo)
+			service := services.NewScheduleService(repo, new(mockedMessageEnqueuer), "TR")
+
+			// Act
+			err := service.CreateSchedule(context.Background(), &schedule)
+
+			// Assert
+			assert.ErrorIs(t, err, domain.ErrInvalidSchedule)
+			repo.AssertNotCalled(t, "CreateSchedule", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPreviewRuns_StopsAtEndDate(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := new(mockedScheduleRepo)
+	service := services.NewScheduleService(repo, new(mockedMessageEnqueuer), "TR")
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	end := start.Add(72 * time.Hour)
+	schedule := &domain.Schedule{ID: 1, Cron: "@daily", Timezone: "Asia/Baku", StartAt: &start, EndAt: &end}
+
+	repo.On("GetSchedule", ctx, uint(1)).Return(schedule, nil)
+
+	// Act
+	runs, err := service.PreviewRuns(ctx, 1, 10)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, runs, 3)
+	for _, run := range runs {
+		assert.False(t, run.Before(start))
+		assert.False(t, run.After(end))
+		assert.Equal(t, 0, run.Hour())
+		assert.Equal(t, "Asia/Baku", run.Location().String())
+	}
+}
+
+func TestRunDueSchedules_EnqueuesForEveryRecipient(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := new(mockedScheduleRepo)
+	enqueuer := new(mockedMessageEnqueuer)
+	service := services.NewScheduleService(repo, enqueuer, "TR")
+
+	due := time.Now().Add(-time.Minute).Truncate(time.Second)
+	schedule := domain.Schedule{ID: 7, Cron: "*/5 * * * *", Timezone: "UTC", TemplateID: 3,
+		Variables: map[string]string{"name": "Elvin"}, Category: "reminders",
+		Recipients: []string{"+905551111001", "+905551111002"}, NextRunAt: &due}
+
+	repo.On("ListDueSchedules", ctx, mock.AnythingOfType("time.Time"), mock.Anything).Return([]domain.Schedule{schedule}, nil)
+	repo.On("AdvanceSchedule", ctx, uint(7), due, mock.MatchedBy(func(next *time.Time) bool {
+		return next != nil && next.After(time.Now())
+	})).Return(true, nil)
+	enqueuer.On("EnqueueMessage", mock.Anything, mock.MatchedBy(func(msg *domain.Message) bool {
+		return msg.TemplateID == 3 && msg.Category == "reminders" && msg.Variables["name"] == "Elvin"
+	})).Return(nil)
+
+	// Act
+	service.RunDueSchedules(ctx)
+
+	// Assert
+	repo.AssertExpectations(t)
+	enqueuer.AssertNumberOfCalls(t, "EnqueueMessage", 2)
+}
+
+func TestRunDueSchedules_OccurrenceClaimedElsewhere(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := new(mockedScheduleRepo)
+	enqueuer := new(mockedMessageEnqueuer)
+	service := services.NewScheduleService(repo, enqueuer, "TR")
+
+	due := time.Now().Add(-time.Minute).Truncate(time.Second)
+	schedule := domain.Schedule{ID: 7, Cron: "*/5 * * * *", Timezone: "UTC", TemplateID: 3,
+		Recipients: []string{"+905551111001"}, NextRunAt: &due}
+
+	repo.On("ListDueSchedules", ctx, mock.AnythingOfType("time.Time"), mock.Anything).Return([]domain.Schedule{schedule}, nil)
+	repo.On("AdvanceSchedule", ctx, uint(7), due, mock.Anything).Return(false, nil)
+
+	// Act
+	service.RunDueSchedules(ctx)
+
+	// Assert
+	enqueuer.AssertNotCalled(t, "EnqueueMessage", mock.Anything, mock.Anything)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,88 @@
+package sms_encoding
+
+import (
+	"github.com/hasElvin/messenger-svc/internal/core/sms"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestAnalyze_GSM7(t *testing.T) {
+	info := sms.Analyze(strings.Repeat("a", 160))
+
+	assert.Equal(t, sms.GSM7, info.Encoding)
+	assert.Equal(t, 160, info.Units)
+	assert.Equal(t, 1, info.Segments)
+}
+
+func TestAnalyze_GSM7Multipart(t *testing.T) {
+	info := sms.Analyze(strings.Repeat("a", 161))
+
+	assert.Equal(t, sms.GSM7, info.Encoding)
+	assert.Equal(t, 2, info.Segments)
+	assert.Equal(t, 153, info.PerSegment)
+}
+
+func TestAnalyze_ExtensionCharactersCountDouble(t *testing.T) {
+	info := sms.Analyze(strings.Repeat("€", 80))
+
+	assert.Equal(t, sms.GSM7, info.Encoding)
+	assert.Equal(t, 160, info.Units)
+	assert.Equal(t, 1, info.Segments)
+}
+
+func TestAnalyze_EscapeNotSplitAcrossSegments(t *testing.T) {
+	// 152 septets then a 2-septet character: it can't start in the first 153-septet segment
+	info := sms.Analyze(strings.Repeat("a", 152) + "€" + strings.Repeat("a", 151) + "€")
+
+	assert.Equal(t, 307, info.Units)
+	assert.Equal(t, 3, info.Segments)
+}
+
+func TestAnalyze_TurkishSingleShift(t *testing.T) {
+	// Ç, Ö and Ü are in the default alphabet; ş, ğ and ı need the Turkish shift table
+	info := sms.Analyze("Çok güzel, şimdi ığdır")
+
+	assert.Equal(t, sms.GSM7Turkish, info.Encoding)
+	assert.Equal(t, 1, info.Segments)
+	assert.Equal(t, 155, info.PerSegment)
+}
+
+func TestAnalyze_TurkishMultipart(t *testing.T) {
+	info := sms.Analyze(strings.Repeat("ş", 78))
+
+	assert.Equal(t, sms.GSM7Turkish, info.Encoding)
+	assert.Equal(t, 156, info.Units)
+	assert.Equal(t, 2, info.Segments)
+	assert.Equal(t, 149, info.PerSegment)
+}
+
+func TestAnalyze_UCS2(t *testing.T) {
+	single := sms.Analyze(strings.Repeat("ж", 70))
+	multi := sms.Analyze(strings.Repeat("ж", 71))
+
+	assert.Equal(t, sms.UCS2, single.Encoding)
+	assert.Equal(t, 1, single.Segments)
+	assert.Equal(t, 2, multi.Segments)
+	assert.Equal(t, 67, multi.PerSegment)
+}
+
+func TestAnalyze_UCS2SurrogatePairs(t *testing.T) {
+	info := sms.Analyze(strings.Repeat("😀", 35))
+
+	assert.Equal(t, sms.UCS2, info.Encoding)
+	assert.Equal(t, 70, info.Units)
+	assert.Equal(t, 1, info.Segments)
+}
+
+func TestSplit_KeepsEscapesTogether(t *testing.T) {
+	parts := sms.Split(strings.Repeat("a", 152)+"€b", sms.GSM7, 153)
+
+	assert.Equal(t, []string{strings.Repeat("a", 152), "€b"}, parts)
+}
+
+func TestSingleCapacity(t *testing.T) {
+	assert.Equal(t, 160, sms.SingleCapacity(sms.GSM7))
+	assert.Equal(t, 155, sms.SingleCapacity(sms.GSM7Turkish))
+	assert.Equal(t, 70, sms.SingleCapacity(sms.UCS2))
+}
@@ -0,0 +1,22 @@
+package sms_encoding
+
+import (
+	"github.com/hasElvin/messenger-svc/internal/core/sms"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTransliterate_Turkish(t *testing.T) {
+	assert.Equal(t, "Sifreniz: 1234. Iyi günler, Gökce!", sms.Transliterate("Şifreniz: 1234. İyi günler, Gökçe!"))
+}
+
+func TestTransliterate_KeepsDefaultAlphabet(t *testing.T) {
+	// ü, ö, é and Ç are in the GSM-7 default alphabet already
+	assert.Equal(t, "Çüö é", sms.Transliterate("Çüö é"))
+}
+
+func TestTransliterate_ReachesGSM7(t *testing.T) {
+	transliterated := sms.Transliterate("Hörmətli müştəri, ödənişiniz “qəbul” edildi…")
+
+	assert.Equal(t, sms.GSM7, sms.Analyze(transliterated).Encoding)
+}
@@ -0,0 +1,64 @@
+package suppression_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+	"time"
+)
+
+type mockedSuppressionRepo struct {
+	mock.Mock
+}
+
+type mockedCacheService struct {
+	mock.Mock
+}
+
+func (r *mockedSuppressionRepo) AddSuppression(ctx context.Context, suppression *domain.Suppression) error {
+	args := r.Called(ctx, suppression)
+	return args.Error(0)
+}
+
+func (r *mockedSuppressionRepo) GetSuppression(ctx context.Context, phone string) (*domain.Suppression, error) {
+	args := r.Called(ctx, phone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Suppression), args.Error(1)
+}
+
+func (r *mockedSuppressionRepo) RemoveSuppression(ctx context.Context, phone string) error {
+	args := r.Called(ctx, phone)
+	return args.Error(0)
+}
+
+func (r *mockedSuppressionRepo) ListSuppressions(ctx context.Context) ([]domain.Suppression, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.Suppression), args.Error(1)
+}
+
+func (c *mockedCacheService) Set(ctx context.Context, key, value string) error {
+	args := c.Called(ctx, key, value)
+	return args.Error(0)
+}
+
+func (c *mockedCacheService) SetWithTTL(ctx context.Context, key, value string, ttl time.Duration) error {
+	args := c.Called(ctx, key, value, ttl)
+	return args.Error(0)
+}
+
+func (c *mockedCacheService) SetIfAbsent(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	args := c.Called(ctx, key, value, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func (c *mockedCacheService) Get(ctx context.Context, key string) (string, error) {
+	args := c.Called(ctx, key)
+	return args.String(0), args.Error(1)
+}
+
+func (c *mockedCacheService) Delete(ctx context.Context, key string) error {
+	args := c.Called(ctx, key)
+	return args.Error(0)
+}
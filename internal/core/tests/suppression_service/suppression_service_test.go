@@ -0,0 +1,171 @@
+package suppression_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestIsSuppressed_CacheHit(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	cacheService.On("Get", ctx, "suppressed:+905551111001").Return("1", nil)
+
+	// Act
+	suppressed, err := service.IsSuppressed(ctx, "+905551111001")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, suppressed)
+	suppressionRepo.AssertNotCalled(t, "GetSuppression", mock.Anything, mock.Anything)
+}
+
+func TestIsSuppressed_CacheMissCachesNegative(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	cacheService.On("Get", ctx, "suppressed:+905551111001").Return("", domain.ErrCacheMiss)
+	suppressionRepo.On("GetSuppression", ctx, "+905551111001").Return(nil, domain.ErrSuppressionNotFound)
+	cacheService.On("SetWithTTL", ctx, "suppressed:+905551111001", "0", mock.Anything).Return(nil)
+
+	// Act
+	suppressed, err := service.IsSuppressed(ctx, "+905551111001")
+
+	// Assert
+	assert.NoError(t, err)
+	assert.False(t, suppressed)
+	cacheService.AssertExpectations(t)
+}
+
+func TestHandleInbound_StopKeywordOptsOut(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	suppressionRepo.On("AddSuppression", ctx, mock.MatchedBy(func(s *domain.Suppression) bool {
+		return s.Phone == "+905551111001" && s.Source == domain.SuppressionKeyword
+	})).Return(nil)
+	cacheService.On("SetWithTTL", ctx, "suppressed:+905551111001", "1", mock.Anything).Return(nil)
+
+	// Act
+	action, err := service.HandleInbound(ctx, domain.InboundMessage{From: "0555 111 10 01", Text: " Stop!! "})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InboundOptOut, action)
+	suppressionRepo.AssertExpectations(t)
+	cacheService.AssertExpectations(t)
+}
+
+func TestHandleInbound_TurkishKeyword(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	suppressionRepo.On("AddSuppression", ctx, mock.Anything).Return(nil)
+	cacheService.On("SetWithTTL", ctx, "suppressed:+905551111001", "1", mock.Anything).Return(nil)
+
+	// Act
+	action, err := service.HandleInbound(ctx, domain.InboundMessage{From: "+905551111001", Text: "iptal"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InboundOptOut, action)
+}
+
+func TestHandleInbound_OtherRepliesIgnored(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	// Act
+	action, err := service.HandleInbound(ctx, domain.InboundMessage{From: "+905551111001", Text: "Stopover in Baku?"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InboundIgnore, action)
+	suppressionRepo.AssertNotCalled(t, "AddSuppression", mock.Anything, mock.Anything)
+}
+
+func TestHandleInbound_ConversationalRepliesIgnored(t *testing.T) {
+	for _, text := range []string{
+		"How do I cancel my booking?",
+		"see you at the end of the week",
+		"Stop please",
+		"Can I start tomorrow?",
+		"don't stop",
+	} {
+		t.Run(text, func(t *testing.T) {
+			// Arrange
+			ctx := context.Background()
+			suppressionRepo := new(mockedSuppressionRepo)
+			cacheService := new(mockedCacheService)
+			service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+			// Act
+			action, err := service.HandleInbound(ctx, domain.InboundMessage{From: "+905551111001", Text: text})
+
+			// Assert
+			assert.NoError(t, err)
+			assert.Equal(t, domain.InboundIgnore, action)
+			suppressionRepo.AssertNotCalled(t, "AddSuppression", mock.Anything, mock.Anything)
+			suppressionRepo.AssertNotCalled(t, "GetSuppression", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandleInbound_StartKeepsManualSuppression(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	suppressionRepo.On("GetSuppression", ctx, "+905551111001").
+		Return(&domain.Suppression{Phone: "+905551111001", Source: domain.SuppressionManual}, nil)
+
+	// Act
+	action, err := service.HandleInbound(ctx, domain.InboundMessage{From: "+905551111001", Text: "START"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InboundIgnore, action)
+	suppressionRepo.AssertNotCalled(t, "RemoveSuppression", mock.Anything, mock.Anything)
+}
+
+func TestHandleInbound_StartLiftsKeywordOptOut(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	suppressionRepo := new(mockedSuppressionRepo)
+	cacheService := new(mockedCacheService)
+	service := services.NewSuppressionService(suppressionRepo, cacheService, "TR", nil, nil)
+
+	suppressionRepo.On("GetSuppression", ctx, "+905551111001").
+		Return(&domain.Suppression{Phone: "+905551111001", Source: domain.SuppressionKeyword}, nil)
+	suppressionRepo.On("RemoveSuppression", ctx, "+905551111001").Return(nil)
+	cacheService.On("SetWithTTL", ctx, "suppressed:+905551111001", "0", mock.Anything).Return(nil)
+
+	// Act
+	action, err := service.HandleInbound(ctx, domain.InboundMessage{From: "+905551111001", Text: "start"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, domain.InboundOptIn, action)
+	suppressionRepo.AssertExpectations(t)
+}
@@ -0,0 +1,44 @@
+package template_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockedTemplateRepo struct {
+	mock.Mock
+}
+
+func (r *mockedTemplateRepo) CreateTemplate(ctx context.Context, template *domain.Template) error {
+	args := r.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (r *mockedTemplateRepo) GetTemplate(ctx context.Context, id uint, version int) (*domain.Template, error) {
+	args := r.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Template), args.Error(1)
+}
+
+func (r *mockedTemplateRepo) ListTemplates(ctx context.Context) ([]domain.Template, error) {
+	args := r.Called(ctx)
+	return args.Get(0).([]domain.Template), args.Error(1)
+}
+
+func (r *mockedTemplateRepo) UpdateTemplate(ctx context.Context, template *domain.Template) error {
+	args := r.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (r *mockedTemplateRepo) DeleteTemplate(ctx context.Context, id uint) error {
+	args := r.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (r *mockedTemplateRepo) ListTemplateVersions(ctx context.Context, id uint) ([]domain.TemplateVersion, error) {
+	args := r.Called(ctx, id)
+	return args.Get(0).([]domain.TemplateVersion), args.Error(1)
+}
@@ -0,0 +1,136 @@
+package template_service
+
+import (
+	"context"
+	"github.com/hasElvin/messenger-svc/internal/core/domain"
+	"github.com/hasElvin/messenger-svc/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+var fallbacks = map[string][]string{"az": {"tr", "en"}, "tr": {"en"}, "de": {"en"}}
+
+func TestCreateTemplate_ExtractsVariables(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	templateRepo := new(mockedTemplateRepo)
+	service := services.NewTemplateService(templateRepo, "en", fallbacks)
+
+	template := &domain.Template{Name: " otp ", Body: "Hi {{name}}, your code is {{ code }}. Bye {{name}}"}
+
+	templateRepo.On("CreateTemplate", ctx, template).Return(nil)
+
+	// Act
+	err := service.CreateTemplate(ctx, template)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "otp", template.Name)
+	assert.Equal(t, []string{"name", "code"}, template.Variables)
+	templateRepo.AssertExpectations(t)
+}
+
+func TestCreateTemplate_RejectsMalformedPlaceholder(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	templateRepo := new(mockedTemplateRepo)
+	service := services.NewTemplateService(templateRepo, "en", fallbacks)
+
+	template := &domain.Template{Name: "otp", Body: "Your code is {{code"}
+
+	// Act
+	err := service.CreateTemplate(ctx, template)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidTemplate)
+	templateRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+}
+
+func TestRender_SubstitutesVariables(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	templateRepo := new(mockedTemplateRepo)
+	service := services.NewTemplateService(templateRepo, "en", fallbacks)
+
+	templateRepo.On("GetTemplate", ctx, uint(7), 0).Return(&domain.Template{
+		ID: 7, Name: "otp", Version: 3, Locale: "en", Body: "Hi {{name}}, code {{ code }}", Variables: []string{"name", "code"},
+	}, nil)
+
+	// Act
+	rendered, err := service.Render(ctx, 7, 0, "", map[string]string{"name": "Ayşe", "code": "1234", "unused": "x"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi Ayşe, code 1234", rendered.Content)
+	assert.Equal(t, 3, rendered.Version)
+	assert.Equal(t, "en", rendered.Locale)
+}
+
+func TestRender_MissingVariables(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	templateRepo := new(mockedTemplateRepo)
+	service := services.NewTemplateService(templateRepo, "en", fallbacks)
+
+	templateRepo.On("GetTemplate", ctx, uint(7), 2).Return(&domain.Template{
+		ID: 7, Name: "otp", Version: 2, Body: "Hi {{name}}, code {{code}}", Variables: []string{"name", "code"},
+	}, nil)
+
+	// Act
+	_, err := service.Render(ctx, 7, 2, "", map[string]string{"name": "Ayşe"})
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidMessage)
+	assert.ErrorContains(t, err, "code")
+}
+
+func TestRender_FollowsFallbackChain(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	templateRepo := new(mockedTemplateRepo)
+	service := services.NewTemplateService(templateRepo, "en", fallbacks)
+
+	templateRepo.On("GetTemplate", ctx, uint(7), 0).Return(&domain.Template{
+		ID: 7, Name: "otp", Version: 1, Locale: "en", Body: "Your code is {{code}}",
+		Variants: map[string]string{"tr": "Kodunuz {{code}}", "de": "Ihr Code ist {{code}}"},
+	}, nil)
+	vars := map[string]string{"code": "1234"}
+
+	// Act
+	azerbaijani, azErr := service.Render(ctx, 7, 0, "az-AZ", vars)
+	german, deErr := service.Render(ctx, 7, 0, "de", vars)
+	french, frErr := service.Render(ctx, 7, 0, "fr", vars)
+
+	// Assert
+	assert.NoError(t, azErr)
+	assert.Equal(t, "tr", azerbaijani.Locale)
+	assert.Equal(t, "Kodunuz 1234", azerbaijani.Content)
+	assert.NoError(t, deErr)
+	assert.Equal(t, "de", german.Locale)
+	assert.NoError(t, frErr)
+	assert.Equal(t, "en", french.Locale)
+}
+
+func TestCreateTemplate_RejectsVariantWithUnknownVariable(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	templateRepo := new(mockedTemplateRepo)
+	service := services.NewTemplateService(templateRepo, "en", fallbacks)
+
+	template := &domain.Template{Name: "otp", Body: "Your code is {{code}}",
+		Variants: map[string]string{"TR": "Merhaba {{name}}, kodunuz {{code}}"}}
+
+	// Act
+	err := service.CreateTemplate(ctx, template)
+
+	// Assert
+	assert.ErrorIs(t, err, domain.ErrInvalidTemplate)
+	templateRepo.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything)
+}
+
+func TestLocaleChain(t *testing.T) {
+	chain := domain.LocaleChain("az-Latn-AZ", fallbacks, "en")
+
+	assert.Equal(t, []string{"az-latn-az", "az-latn", "az", "tr", "en"}, chain)
+}